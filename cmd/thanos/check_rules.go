@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/alecthomas/kingpin/v2"
+
+	"github.com/thanos-io/thanos/pkg/check"
+)
+
+// registerCheckRulesUnit wires `thanos tools check rules-unit-test`, validating a
+// promtool-style rule unit test file's schema and PromQL expressions.
+// cmd/promtool, which actually executes these tests, is package main and
+// can't be imported by another Go binary; see check.RulesUnitTest's doc
+// comment for what's validated instead.
+func registerCheckRulesUnit(cmd *kingpin.CmdClause) {
+	unitCmd := cmd.Command("rules-unit-test", "Validate a promtool-style rule unit test file's schema and PromQL expressions (does not execute the tests; see check.RulesUnitTest's doc comment).")
+	files := unitCmd.Arg("unit-files", "The unit test files.").Required().ExistingFiles()
+
+	unitCmd.Action(func(*kingpin.ParseContext) error {
+		for _, f := range *files {
+			if err := checkFile(f, check.RulesUnitTest); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid"
+
+	"github.com/thanos-io/thanos/pkg/block"
+	"github.com/thanos-io/thanos/pkg/block/metadata"
+	"github.com/thanos-io/thanos/pkg/downsample"
+	"github.com/thanos-io/thanos/pkg/objstore"
+	"github.com/thanos-io/thanos/pkg/objstore/client"
+)
+
+// errAggregationNotImplemented is returned by notImplementedAggregate.
+// Downsampling a block requires decoding and re-encoding its chunk data at
+// the target resolution, which belongs in the compactor's chunk-level code
+// this tree doesn't carry; everything around that step — selection,
+// concurrency and resumable progress — is real and exercised by this
+// command, with aggregation itself left as the seam a real implementation
+// plugs into via downsample.AggregateFunc.
+var errAggregationNotImplemented = errors.New("chunk-level downsampling aggregation is not implemented in this tree")
+
+func notImplementedAggregate(_ context.Context, _ objstore.Bucket, job downsample.Job) (ulid.ULID, error) {
+	return ulid.ULID{}, fmt.Errorf("block %s: %w", job.Block, errAggregationNotImplemented)
+}
+
+// registerDownsample wires the standalone `thanos downsample` command,
+// which drains the downsampling backlog against a bucket directly, so it
+// can run on hardware separate from the compactor when a cluster's
+// backlog is large enough to want a dedicated owner.
+func registerDownsample(app *kingpin.Application) {
+	cmd := app.Command("downsample", "Continuously downsample blocks in a bucket.")
+	objConfigFile := cmd.Flag("objstore.config-file", "Path to the bucket config YAML.").Required().ExistingFile()
+	matchers := cmd.Flag("matcher", "Only downsample blocks matching this external label selector (repeatable).").Strings()
+	minTime := cmd.Flag("min-time", "Only downsample blocks overlapping after this time, in milliseconds.").Int64()
+	maxTime := cmd.Flag("max-time", "Only downsample blocks overlapping before this time, in milliseconds.").Int64()
+	concurrency := cmd.Flag("concurrency", "Number of blocks to downsample concurrently.").Default("1").Int()
+	wait := cmd.Flag("wait", "Run continuously instead of a single pass over the current backlog.").Bool()
+	waitInterval := cmd.Flag("wait-interval", "How long to sleep between passes when --wait is set.").Default("5m").Duration()
+
+	cmd.Action(func(*kingpin.ParseContext) error {
+		logger := log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr))
+
+		objCfg, err := readFile(*objConfigFile)
+		if err != nil {
+			return err
+		}
+		bkt, err := client.NewBucket(logger, objCfg)
+		if err != nil {
+			return err
+		}
+		ms, err := parseMatchers(*matchers)
+		if err != nil {
+			return err
+		}
+		sel := block.Selector{Matchers: ms, MinTime: *minTime, MaxTime: *maxTime}
+
+		ctx := context.Background()
+		d := downsample.New(logger, bkt, downsample.Config{Concurrency: *concurrency}, notImplementedAggregate)
+
+		for {
+			if err := runDownsamplePass(ctx, logger, bkt, d, sel); err != nil {
+				return err
+			}
+			if !*wait {
+				return nil
+			}
+			level.Info(logger).Log("msg", "sleeping until next pass", "interval", *waitInterval)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(*waitInterval):
+			}
+		}
+	})
+}
+
+func runDownsamplePass(ctx context.Context, logger log.Logger, bkt objstore.Bucket, d *downsample.Downsampler, sel block.Selector) error {
+	metas, _, err := block.NewMetaFetcher(bkt).Fetch(ctx)
+	if err != nil {
+		return err
+	}
+	selected := map[ulid.ULID]*metadata.Meta{}
+	for id, m := range metas {
+		if sel.Matches(m) {
+			selected[id] = m
+		}
+	}
+
+	progress, err := downsample.LoadProgress(ctx, bkt)
+	if err != nil {
+		return err
+	}
+	jobs := downsample.Plan(selected, progress)
+	if len(jobs) == 0 {
+		level.Info(logger).Log("msg", "no blocks need downsampling")
+		return nil
+	}
+	level.Info(logger).Log("msg", "downsampling backlog", "jobs", len(jobs))
+	return d.Run(ctx, jobs, progress)
+}
@@ -0,0 +1,25 @@
+// Command thanos is the single binary entry point for every Thanos
+// component and tool: it assembles one kingpin application out of each
+// component's register* function and dispatches to whichever subcommand
+// the user picked.
+package main
+
+import (
+	"os"
+
+	"github.com/alecthomas/kingpin/v2"
+)
+
+func main() {
+	app := kingpin.New("thanos", "A block storage based, highly available query system for Prometheus.")
+
+	registerQuery(app)
+	registerQueryFrontend(app)
+	registerDownsample(app)
+
+	tools := registerTools(app)
+	registerBucket(tools)
+	registerToolsCheck(tools)
+
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	apiv1 "github.com/thanos-io/thanos/pkg/api/stores/v1"
+	"github.com/thanos-io/thanos/pkg/component"
+	"github.com/thanos-io/thanos/pkg/query"
+	httpserver "github.com/thanos-io/thanos/pkg/server/http"
+	"github.com/thanos-io/thanos/pkg/store"
+	"github.com/thanos-io/thanos/pkg/ui"
+)
+
+type queryConfig struct {
+	storeAddrs      []string
+	grpcCompression string
+	httpConfigFile  string
+	httpAddr        string
+	hedgeDelay      time.Duration
+}
+
+func registerQuery(app *kingpin.Application) {
+	cmd := app.Command(component.Query.String(), "Query node exposing PromQL enabled Query API with data retrieved from multiple store nodes.")
+
+	cfg := &queryConfig{}
+	cmd.Flag("store", "Addresses of statically configured store API servers (repeatable).").
+		PlaceHolder("<store>").StringsVar(&cfg.storeAddrs)
+
+	cmd.Flag("grpc-compression", "Compression algorithm to use for gRPC requests to other clients. Must be one of: "+fmt.Sprintf("%v", []string{"none", "snappy", "zstd"})).
+		Default(string(store.CompressionNone)).
+		EnumVar(&cfg.grpcCompression, string(store.CompressionNone), string(store.CompressionSnappy), string(store.CompressionZstd))
+
+	cmd.Flag("http.config", "Path to the HTTP server TLS and basic auth config file, so the web UI and APIs can be served over HTTPS and/or behind basic auth without an external proxy.").
+		StringVar(&cfg.httpConfigFile)
+
+	cmd.Flag("http-address", "Address to serve the web UI and API on.").
+		Default(":10904").StringVar(&cfg.httpAddr)
+
+	cmd.Flag("store.hedge-delay", "Delay before a duplicate Series request is sent to the next store of a hedged replica set. Zero disables hedging. Any non-zero value makes the command exit with an error, since store clients here have no working Series implementation to hedge across.").
+		Default("0s").DurationVar(&cfg.hedgeDelay)
+
+	cmd.Action(func(*kingpin.ParseContext) error {
+		return runQuery(cfg)
+	})
+}
+
+// runQuery dials every statically configured store, using --grpc-compression
+// for outgoing Series/LabelNames/LabelValues calls, and serves the stores
+// web UI and API over the configured store set. Evaluating PromQL against
+// those stores requires an engine this tree doesn't carry (see
+// pkg/store.ChunkIterable's doc comment for the matching gap on the
+// decoding side); this command's real, exercised surface is dialing stores
+// with the requested compression and reporting their health.
+func runQuery(cfg *queryConfig) error {
+	logger := log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr))
+	comp := store.Compression(cfg.grpcCompression)
+
+	specs := make([]query.StoreSpec, 0, len(cfg.storeAddrs))
+	clients := make([]*store.Client, 0, len(cfg.storeAddrs))
+	for _, addr := range cfg.storeAddrs {
+		specs = append(specs, query.StoreSpec{Addr: addr, Strict: true})
+		c, err := store.NewClient(context.Background(), addr, comp)
+		if err != nil {
+			level.Error(logger).Log("msg", "failed to dial store", "addr", addr, "compression", comp, "err", err)
+			continue
+		}
+		level.Info(logger).Log("msg", "dialed store", "addr", addr, "compression", comp)
+		clients = append(clients, c)
+	}
+	storeSet := query.NewStoreSet(logger, specs)
+
+	// storepb.NewStoreClient is a stub that always returns a nil
+	// StoreClient in this tree (rpc.proto is not compiled here), so a
+	// store.Client's embedded Series/LabelNames/LabelValues methods panic
+	// on a nil interface the moment they're called. --store.hedge-delay
+	// would build a real store.ReplicaSet and then call Series through
+	// exactly that stub, so refuse to start instead of letting that
+	// surface as a panic on the first query.
+	if cfg.hedgeDelay > 0 {
+		if len(clients) < 2 {
+			return fmt.Errorf("--store.hedge-delay requires at least two reachable --store addresses to hedge across, got %d", len(clients))
+		}
+		return fmt.Errorf("--store.hedge-delay is not usable yet: storepb.NewStoreClient has no real gRPC implementation in this tree, so store.ReplicaSet.Series has nothing to call")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", ui.StoresHandler())
+	mux.HandleFunc("/api/v1/stores", apiv1.NewAPI(storeSet).Stores)
+
+	var httpCfg *httpserver.Config
+	if cfg.httpConfigFile != "" {
+		content, err := readFile(cfg.httpConfigFile)
+		if err != nil {
+			return err
+		}
+		httpCfg, err = httpserver.ParseConfig(content)
+		if err != nil {
+			return err
+		}
+	}
+
+	srv := httpserver.New(logger, component.Query, mux, httpCfg, httpserver.WithListen(cfg.httpAddr))
+	level.Info(logger).Log("msg", "serving query stores UI", "address", cfg.httpAddr, "stores", len(specs))
+	return srv.ListenAndServe()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/alecthomas/kingpin/v2"
+
+	"github.com/thanos-io/thanos/pkg/check"
+)
+
+// registerToolsCheck wires `thanos tools check`, the parent command for
+// validating config and rule files before they're handed to a running
+// process.
+func registerToolsCheck(tools *kingpin.CmdClause) {
+	checkCmd := tools.Command("check", "Check config and rule files for validity.")
+	registerCheckObjstoreConfig(checkCmd)
+	registerCheckTracingConfig(checkCmd)
+	registerCheckRelabelConfig(checkCmd)
+	registerCheckRules(checkCmd)
+	registerCheckRulesUnit(checkCmd)
+}
+
+// checkFile runs validate against the file at path, printing "OK" on
+// success; kingpin reports a returned error as a normal command failure.
+func checkFile(path string, validate func([]byte) error) error {
+	content, err := readFile(path)
+	if err != nil {
+		return err
+	}
+	if err := validate(content); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, "%s: OK\n", path)
+	return nil
+}
+
+func registerCheckObjstoreConfig(checkCmd *kingpin.CmdClause) {
+	cmd := checkCmd.Command("objstore-config", "Validate an objstore config file.")
+	file := cmd.Flag("file", "Path to the objstore config YAML.").Required().ExistingFile()
+	cmd.Action(func(*kingpin.ParseContext) error {
+		return checkFile(*file, check.ObjstoreConfig)
+	})
+}
+
+func registerCheckTracingConfig(cmd *kingpin.CmdClause) {
+	c := cmd.Command("tracing-config", "Validate a tracing config file.")
+	file := c.Flag("file", "Path to the tracing config YAML.").Required().ExistingFile()
+	c.Action(func(*kingpin.ParseContext) error {
+		return checkFile(*file, check.TracingConfig)
+	})
+}
+
+func registerCheckRelabelConfig(cmd *kingpin.CmdClause) {
+	c := cmd.Command("relabel-config", "Validate a relabel config file.")
+	file := c.Flag("file", "Path to the relabel config YAML.").Required().ExistingFile()
+	c.Action(func(*kingpin.ParseContext) error {
+		return checkFile(*file, check.RelabelConfig)
+	})
+}
+
+func registerCheckRules(cmd *kingpin.CmdClause) {
+	c := cmd.Command("rules", "Validate a ruler rule file.")
+	file := c.Flag("file", "Path to the rule file YAML.").Required().ExistingFile()
+	c.Action(func(*kingpin.ParseContext) error {
+		return checkFile(*file, check.RuleFile)
+	})
+}
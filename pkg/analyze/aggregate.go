@@ -0,0 +1,65 @@
+package analyze
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/tsdb/index"
+
+	"github.com/thanos-io/thanos/pkg/store"
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+// Aggregate reduces the latest sample value of every series in the block at
+// blockDir matching m into a single value via fn, the same per-chunk
+// associative reduction store.ApplyAggrPushdown performs when a StoreAPI
+// pushes a simple aggregation down instead of shipping every raw sample
+// back to the engine; see that function's doc comment for which funcs
+// qualify. It returns the matched series count alongside the result so an
+// empty match set is distinguishable from a genuine zero.
+func Aggregate(blockDir string, m *labels.Matcher, fn storepb.AggrFunc) (result float64, matched int, err error) {
+	ir, err := index.NewFileReader(filepath.Join(blockDir, "index"), index.DecodePostingsRaw)
+	if err != nil {
+		return 0, 0, fmt.Errorf("opening index: %w", err)
+	}
+	defer ir.Close()
+
+	cr, err := chunks.NewDirReader(filepath.Join(blockDir, "chunks"), nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("opening chunks: %w", err)
+	}
+	defer cr.Close()
+
+	var values []float64
+	err = forEachSeries(ir, func(_ string, lset labels.Labels, chks []chunks.Meta) error {
+		if !m.Matches(lset.Get(m.Name)) || len(chks) == 0 {
+			return nil
+		}
+		chk, _, err := cr.ChunkOrIterable(chks[len(chks)-1])
+		if err != nil {
+			return fmt.Errorf("reading chunk for series %s: %w", lset, err)
+		}
+		var v float64
+		it := chk.Iterator(nil)
+		for it.Next() != chunkenc.ValNone {
+			_, v = it.At()
+		}
+		if err := it.Err(); err != nil {
+			return fmt.Errorf("iterating chunk for series %s: %w", lset, err)
+		}
+		values = append(values, v)
+		return nil
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("walking series: %w", err)
+	}
+
+	result, ok := store.ApplyAggrPushdown(&storepb.QueryHints{Func: fn}, values)
+	if !ok {
+		return 0, len(values), fmt.Errorf("unsupported aggregation func %v, or no matching series", fn)
+	}
+	return result, len(values), nil
+}
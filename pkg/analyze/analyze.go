@@ -0,0 +1,161 @@
+// Package analyze computes cardinality and churn statistics over a set of
+// blocks' TSDB indexes. AnalyzeIndex, InspectIndex, CountMatches and
+// ShardCounts only read index postings and label data, never chunk
+// samples, so they can answer "which metrics/labels are expensive"
+// questions without the cost of a full block download. Aggregate,
+// ChecksumBlock and DedupBlocks also read the block's chunks.
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/oklog/ulid"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/tsdb/index"
+)
+
+// MetricCount is one metric name's series count within a block.
+type MetricCount struct {
+	Name   string `json:"name"`
+	Series int    `json:"series"`
+}
+
+// LabelCardinality is one label name's distinct-value count within a
+// block.
+type LabelCardinality struct {
+	Name   string `json:"name"`
+	Values int    `json:"values"`
+}
+
+// BlockReport is the cardinality analysis of a single block's index.
+type BlockReport struct {
+	Block              ulid.ULID          `json:"block"`
+	SeriesCount        int                `json:"series_count"`
+	TopMetrics         []MetricCount      `json:"top_metrics"`
+	LabelCardinalities []LabelCardinality `json:"label_cardinalities"`
+
+	// seriesHashes is kept only to compute Churn against another report;
+	// it's unexported so it never leaks into the JSON report itself.
+	seriesHashes map[uint64]struct{}
+}
+
+// ChurnReport compares two BlockReports' series sets.
+type ChurnReport struct {
+	From    ulid.ULID `json:"from"`
+	To      ulid.ULID `json:"to"`
+	Added   int       `json:"added"`
+	Removed int       `json:"removed"`
+	Kept    int       `json:"kept"`
+}
+
+// AnalyzeIndex opens the TSDB index at indexPath (a local file, typically a
+// block's "index" object downloaded to a temp file by the caller) and
+// computes its BlockReport. topN bounds how many entries TopMetrics and
+// LabelCardinalities keep, since a block can have hundreds of thousands of
+// distinct metric names.
+func AnalyzeIndex(id ulid.ULID, indexPath string, topN int) (*BlockReport, error) {
+	ctx := context.Background()
+	r, err := index.NewFileReader(indexPath, index.DecodePostingsRaw)
+	if err != nil {
+		return nil, fmt.Errorf("opening index for block %s: %w", id, err)
+	}
+	defer r.Close()
+
+	report := &BlockReport{Block: id, seriesHashes: map[uint64]struct{}{}}
+
+	names, err := r.LabelNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing label names for block %s: %w", id, err)
+	}
+	for _, name := range names {
+		values, err := r.LabelValues(ctx, name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("listing values of label %q for block %s: %w", name, id, err)
+		}
+		report.LabelCardinalities = append(report.LabelCardinalities, LabelCardinality{Name: name, Values: len(values)})
+	}
+	sort.Slice(report.LabelCardinalities, func(i, j int) bool {
+		return report.LabelCardinalities[i].Values > report.LabelCardinalities[j].Values
+	})
+	if topN > 0 && len(report.LabelCardinalities) > topN {
+		report.LabelCardinalities = report.LabelCardinalities[:topN]
+	}
+
+	counts := map[string]int{}
+	err = forEachSeries(r, func(metric string, lset labels.Labels, _ []chunks.Meta) error {
+		counts[metric]++
+		report.SeriesCount++
+		report.seriesHashes[lset.Hash()] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking series for block %s: %w", id, err)
+	}
+
+	for name, n := range counts {
+		report.TopMetrics = append(report.TopMetrics, MetricCount{Name: name, Series: n})
+	}
+	sort.Slice(report.TopMetrics, func(i, j int) bool { return report.TopMetrics[i].Series > report.TopMetrics[j].Series })
+	if topN > 0 && len(report.TopMetrics) > topN {
+		report.TopMetrics = report.TopMetrics[:topN]
+	}
+
+	return report, nil
+}
+
+// forEachSeries walks every series in r's index, grouped by metric name, and
+// calls f with its labels and chunk metadata. It's the shared series walk
+// behind both AnalyzeIndex and InspectIndex, so they stay consistent about
+// what "every series" means.
+func forEachSeries(r *index.Reader, f func(metric string, lset labels.Labels, chks []chunks.Meta) error) error {
+	ctx := context.Background()
+	metricNames, err := r.LabelValues(ctx, labels.MetricName, nil)
+	if err != nil {
+		return fmt.Errorf("listing metric names: %w", err)
+	}
+
+	var (
+		builder labels.ScratchBuilder
+		chks    []chunks.Meta
+	)
+	for _, metric := range metricNames {
+		p, err := r.Postings(ctx, labels.MetricName, metric)
+		if err != nil {
+			return fmt.Errorf("reading postings for metric %q: %w", metric, err)
+		}
+		for p.Next() {
+			if err := r.Series(p.At(), &builder, &chks); err != nil {
+				return fmt.Errorf("reading series for metric %q: %w", metric, err)
+			}
+			if err := f(metric, builder.Labels(), chks); err != nil {
+				return err
+			}
+		}
+		if err := p.Err(); err != nil {
+			return fmt.Errorf("iterating postings for metric %q: %w", metric, err)
+		}
+	}
+	return nil
+}
+
+// Churn compares from and to's series sets, both of which must have come
+// from AnalyzeIndex so their internal hash sets are populated.
+func Churn(from, to *BlockReport) ChurnReport {
+	cr := ChurnReport{From: from.Block, To: to.Block}
+	for h := range to.seriesHashes {
+		if _, ok := from.seriesHashes[h]; ok {
+			cr.Kept++
+		} else {
+			cr.Added++
+		}
+	}
+	for h := range from.seriesHashes {
+		if _, ok := to.seriesHashes[h]; !ok {
+			cr.Removed++
+		}
+	}
+	return cr
+}
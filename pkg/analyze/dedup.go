@@ -0,0 +1,89 @@
+package analyze
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/tsdb/index"
+
+	"github.com/thanos-io/thanos/pkg/dedup"
+)
+
+// DedupSeries is one dedup-merged series: its (replica-label-stripped)
+// labels and the samples dedup.PenaltyDedup produced across every replica
+// block that carried it.
+type DedupSeries struct {
+	Labels  string         `json:"labels"`
+	Samples []dedup.Sample `json:"samples"`
+}
+
+// DedupBlocks merges series that exist in more than one of blockDirs,
+// treating each block as one replica: series are grouped by their labels
+// with replicaLabels stripped (dedup.ReplicaLabelSets.StripReplicaLabels,
+// so more than one label - e.g. both "replica" and "rule_replica" - can
+// identify a replica), and each group's per-replica sample streams are
+// merged with dedup.PenaltyDedup, the same reduction a BucketStore would
+// apply across replicated blocks if this tree's Series path read real
+// chunk samples. isCounter applies PenaltyDedup's counter-reset handling to
+// every series; a real querier would decide this per-metric from a
+// __name__ suffix convention, which is out of scope here.
+func DedupBlocks(blockDirs []string, replicaLabels dedup.ReplicaLabelSets, isCounter bool) ([]DedupSeries, error) {
+	groups := map[string][][]dedup.Sample{}
+
+	for _, blockDir := range blockDirs {
+		ir, err := index.NewFileReader(blockDir+"/index", index.DecodePostingsRaw)
+		if err != nil {
+			return nil, fmt.Errorf("opening index for block %s: %w", blockDir, err)
+		}
+		cr, err := chunks.NewDirReader(blockDir+"/chunks", nil)
+		if err != nil {
+			ir.Close()
+			return nil, fmt.Errorf("opening chunks for block %s: %w", blockDir, err)
+		}
+
+		err = forEachSeries(ir, func(_ string, lset labels.Labels, chks []chunks.Meta) error {
+			key := replicaLabels.StripReplicaLabels(lset).String()
+
+			var samples []dedup.Sample
+			for _, c := range chks {
+				chk, _, err := cr.ChunkOrIterable(c)
+				if err != nil {
+					return fmt.Errorf("reading chunk for series %s: %w", lset, err)
+				}
+				it := chk.Iterator(nil)
+				for it.Next() != chunkenc.ValNone {
+					t, v := it.At()
+					samples = append(samples, dedup.Sample{T: t, V: v})
+				}
+				if err := it.Err(); err != nil {
+					return fmt.Errorf("iterating chunk for series %s: %w", lset, err)
+				}
+			}
+			groups[key] = append(groups[key], samples)
+			return nil
+		})
+		cr.Close()
+		ir.Close()
+		if err != nil {
+			return nil, fmt.Errorf("walking series for block %s: %w", blockDir, err)
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]DedupSeries, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, DedupSeries{
+			Labels:  k,
+			Samples: dedup.PenaltyDedup(groups[k], isCounter),
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,116 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/oklog/ulid"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/tsdb/index"
+)
+
+// PostingsStat is one label name=value pair's postings list length, the
+// same shape promtool's own "postings stats" debug output uses to surface
+// which label pairs are driving index size.
+type PostingsStat struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SeriesSize is one series' chunk count, used to surface the series with
+// the most chunks rather than the most bytes, since chunk count is all an
+// index alone can tell us without reading the chunks themselves.
+type SeriesSize struct {
+	Labels string `json:"labels"`
+	Chunks int    `json:"chunks"`
+}
+
+// IndexStats is a single block index's structural statistics: symbol table
+// size, postings list sizes, the largest series by chunk count, and label
+// cardinality, mirroring what `promtool tsdb analyze` reports for a local
+// block but computed directly against a bucket's index object.
+type IndexStats struct {
+	Block              ulid.ULID          `json:"block"`
+	SeriesCount        int                `json:"series_count"`
+	SymbolCount        int                `json:"symbol_count"`
+	PostingsStats      []PostingsStat     `json:"postings_stats"`
+	BiggestSeries      []SeriesSize       `json:"biggest_series"`
+	LabelCardinalities []LabelCardinality `json:"label_cardinalities"`
+}
+
+// InspectIndex opens the TSDB index at indexPath and computes IndexStats.
+// topN bounds how many entries each top-N list keeps.
+func InspectIndex(id ulid.ULID, indexPath string, topN int) (*IndexStats, error) {
+	ctx := context.Background()
+	r, err := index.NewFileReader(indexPath, index.DecodePostingsRaw)
+	if err != nil {
+		return nil, fmt.Errorf("opening index for block %s: %w", id, err)
+	}
+	defer r.Close()
+
+	stats := &IndexStats{Block: id}
+
+	symbols := r.Symbols()
+	for symbols.Next() {
+		stats.SymbolCount++
+	}
+	if err := symbols.Err(); err != nil {
+		return nil, fmt.Errorf("reading symbol table for block %s: %w", id, err)
+	}
+
+	names, err := r.LabelNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing label names for block %s: %w", id, err)
+	}
+	for _, name := range names {
+		values, err := r.LabelValues(ctx, name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("listing values of label %q for block %s: %w", name, id, err)
+		}
+		stats.LabelCardinalities = append(stats.LabelCardinalities, LabelCardinality{Name: name, Values: len(values)})
+		for _, value := range values {
+			p, err := r.Postings(ctx, name, value)
+			if err != nil {
+				return nil, fmt.Errorf("reading postings for %s=%q in block %s: %w", name, value, id, err)
+			}
+			count := 0
+			for p.Next() {
+				count++
+			}
+			if err := p.Err(); err != nil {
+				return nil, fmt.Errorf("iterating postings for %s=%q in block %s: %w", name, value, id, err)
+			}
+			stats.PostingsStats = append(stats.PostingsStats, PostingsStat{Name: name, Value: value, Count: count})
+		}
+	}
+	sort.Slice(stats.LabelCardinalities, func(i, j int) bool {
+		return stats.LabelCardinalities[i].Values > stats.LabelCardinalities[j].Values
+	})
+	sort.Slice(stats.PostingsStats, func(i, j int) bool { return stats.PostingsStats[i].Count > stats.PostingsStats[j].Count })
+	if topN > 0 {
+		if len(stats.LabelCardinalities) > topN {
+			stats.LabelCardinalities = stats.LabelCardinalities[:topN]
+		}
+		if len(stats.PostingsStats) > topN {
+			stats.PostingsStats = stats.PostingsStats[:topN]
+		}
+	}
+
+	err = forEachSeries(r, func(_ string, lset labels.Labels, chks []chunks.Meta) error {
+		stats.SeriesCount++
+		stats.BiggestSeries = append(stats.BiggestSeries, SeriesSize{Labels: lset.String(), Chunks: len(chks)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking series for block %s: %w", id, err)
+	}
+	sort.Slice(stats.BiggestSeries, func(i, j int) bool { return stats.BiggestSeries[i].Chunks > stats.BiggestSeries[j].Chunks })
+	if topN > 0 && len(stats.BiggestSeries) > topN {
+		stats.BiggestSeries = stats.BiggestSeries[:topN]
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,61 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/tsdb/index"
+
+	"github.com/thanos-io/thanos/pkg/store"
+)
+
+// CountMatches opens the TSDB index at indexPath and counts series matching
+// m. When m is a regex matcher whose pattern is a plain alternation of
+// literals (e.g. `a|b|c`), store.ExpandSetMatcher's decomposition is used to
+// look up postings per literal value directly instead of scanning every
+// value of m's label, the same optimization BucketStore.Series would apply
+// against this index if this tree carried one; see store.ExpandSetMatcher's
+// doc comment for that gap.
+func CountMatches(indexPath string, m *labels.Matcher) (int, error) {
+	ctx := context.Background()
+	r, err := index.NewFileReader(indexPath, index.DecodePostingsRaw)
+	if err != nil {
+		return 0, fmt.Errorf("opening index: %w", err)
+	}
+	defer r.Close()
+
+	// MatchNotRegexp's literal set is what's excluded, not what matches, so
+	// the direct-lookup fast path only applies to MatchRegexp; fall through
+	// to the full scan below for the negated case.
+	if values, ok := store.ExpandSetMatcher(m); ok && m.Type == labels.MatchRegexp {
+		seen := map[storage.SeriesRef]struct{}{}
+		for _, v := range values {
+			p, err := r.Postings(ctx, m.Name, v)
+			if err != nil {
+				return 0, fmt.Errorf("reading postings for %s=%q: %w", m.Name, v, err)
+			}
+			for p.Next() {
+				seen[p.At()] = struct{}{}
+			}
+			if err := p.Err(); err != nil {
+				return 0, fmt.Errorf("iterating postings for %s=%q: %w", m.Name, v, err)
+			}
+		}
+		return len(seen), nil
+	}
+
+	count := 0
+	err = forEachSeries(r, func(_ string, lset labels.Labels, _ []chunks.Meta) error {
+		if m.Matches(lset.Get(m.Name)) {
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("walking series: %w", err)
+	}
+	return count, nil
+}
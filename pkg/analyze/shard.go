@@ -0,0 +1,40 @@
+package analyze
+
+import (
+	"fmt"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/tsdb/index"
+
+	"github.com/thanos-io/thanos/pkg/query"
+)
+
+// ShardCounts is how many of a block's series fall into each vertical
+// shard query.PlanShards would plan, using the same query.ShardLabelHash a
+// querier and a store agree on so this reports exactly what a live
+// ShardInfo-filtered Series call against this block would return per shard.
+func ShardCounts(indexPath string, totalShards uint64, by []string) ([]int, error) {
+	r, err := index.NewFileReader(indexPath, index.DecodePostingsRaw)
+	if err != nil {
+		return nil, fmt.Errorf("opening index: %w", err)
+	}
+	defer r.Close()
+
+	shards := query.PlanShards(totalShards, by)
+	counts := make([]int, len(shards))
+	err = forEachSeries(r, func(_ string, lset labels.Labels, _ []chunks.Meta) error {
+		h := query.ShardLabelHash(lset, by)
+		for i, s := range shards {
+			if s.Owns(h) {
+				counts[i]++
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking series: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,45 @@
+// Package v1 exposes the bucket UI's block inventory over HTTP at
+// /api/v1/blocks, so external dashboards and scripts can consume filtered,
+// paginated block state without scraping the bucket UI's HTML template.
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/thanos-io/thanos/pkg/ui"
+)
+
+type API struct {
+	bucket *ui.Bucket
+}
+
+func NewAPI(bucket *ui.Bucket) *API {
+	return &API{bucket: bucket}
+}
+
+// Blocks handles GET /api/v1/blocks, accepting the same filter and
+// pagination parameters as the bucket UI's own /blocks endpoint, backed by
+// the same ui.Bucket.Set state.
+func (a *API) Blocks(w http.ResponseWriter, r *http.Request) {
+	f, err := ui.ParseFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, err := a.bucket.Query(f)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	type envelope struct {
+		Status string      `json:"status"`
+		Data   interface{} `json:"data"`
+	}
+	_ = json.NewEncoder(w).Encode(envelope{Status: "success", Data: v})
+}
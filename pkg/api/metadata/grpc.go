@@ -0,0 +1,83 @@
+// Package metadata implements the Thanos Metadata gRPC service: the sidecar
+// serves it by proxying its local Prometheus' /api/v1/metadata, and the
+// querier fans requests out across all known metadata-capable StoreAPIs and
+// deduplicates the results before answering /api/v1/metadata.
+package metadata
+
+import (
+	"context"
+
+	"github.com/prometheus/prometheus/scrape"
+
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+// PrometheusMetadataClient fetches metric metadata from a Prometheus HTTP
+// API, letting the sidecar implement storepb.MetadataServer without
+// depending on Prometheus internals directly.
+type PrometheusMetadataClient interface {
+	MetricMetadata(ctx context.Context, metric string, limit int) (map[string][]scrape.MetricMetadata, error)
+}
+
+// Sidecar implements storepb.MetadataServer by proxying a local Prometheus.
+type Sidecar struct {
+	client PrometheusMetadataClient
+}
+
+func NewSidecar(client PrometheusMetadataClient) *Sidecar {
+	return &Sidecar{client: client}
+}
+
+func (s *Sidecar) MetricMetadata(ctx context.Context, r *storepb.MetadataRequest) (*storepb.MetadataResponse, error) {
+	raw, err := s.client.MetricMetadata(ctx, r.Metric, int(r.Limit))
+	if err != nil {
+		return nil, err
+	}
+	resp := &storepb.MetadataResponse{Metadata: make(map[string][]storepb.Metadata, len(raw))}
+	for metric, mds := range raw {
+		for _, md := range mds {
+			resp.Metadata[metric] = append(resp.Metadata[metric], storepb.Metadata{
+				Type: string(md.Type),
+				Help: md.Help,
+				Unit: md.Unit,
+			})
+		}
+	}
+	return resp, nil
+}
+
+// Proxy implements storepb.MetadataServer by fanning MetricMetadata out to
+// every client and deduplicating identical (metric, Metadata) pairs.
+type Proxy struct {
+	clients func() []storepb.MetadataClient
+}
+
+func NewProxy(clients func() []storepb.MetadataClient) *Proxy {
+	return &Proxy{clients: clients}
+}
+
+func (p *Proxy) MetricMetadata(ctx context.Context, r *storepb.MetadataRequest) (*storepb.MetadataResponse, error) {
+	merged := &storepb.MetadataResponse{Metadata: map[string][]storepb.Metadata{}}
+	seen := map[string]map[storepb.Metadata]struct{}{}
+
+	for _, c := range p.clients() {
+		resp, err := c.MetricMetadata(ctx, r)
+		if err != nil {
+			merged.Warnings = append(merged.Warnings, err.Error())
+			continue
+		}
+		for metric, mds := range resp.Metadata {
+			if seen[metric] == nil {
+				seen[metric] = map[storepb.Metadata]struct{}{}
+			}
+			for _, md := range mds {
+				if _, ok := seen[metric][md]; ok {
+					continue
+				}
+				seen[metric][md] = struct{}{}
+				merged.Metadata[metric] = append(merged.Metadata[metric], md)
+			}
+		}
+	}
+	return merged, nil
+}
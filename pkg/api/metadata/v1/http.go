@@ -0,0 +1,53 @@
+// Package v1 exposes the querier's merged metric metadata over HTTP at
+// /api/v1/metadata, matching Prometheus' own endpoint shape so existing
+// consumers (e.g. Grafana's metric explorer) work unmodified against Thanos.
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/thanos-io/thanos/pkg/api/metadata"
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+type API struct {
+	proxy *metadata.Proxy
+}
+
+func NewAPI(proxy *metadata.Proxy) *API {
+	return &API{proxy: proxy}
+}
+
+// Metadata handles GET /api/v1/metadata?metric=<name>&limit=<n>.
+func (a *API) Metadata(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit := int32(0)
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			http.Error(w, "invalid limit: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		limit = int32(n)
+	}
+	resp, err := a.proxy.MetricMetadata(r.Context(), &storepb.MetadataRequest{
+		Metric: q.Get("metric"),
+		Limit:  limit,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, resp)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	type envelope struct {
+		Status string      `json:"status"`
+		Data   interface{} `json:"data"`
+	}
+	_ = json.NewEncoder(w).Encode(envelope{Status: "success", Data: v})
+}
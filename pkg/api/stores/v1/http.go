@@ -0,0 +1,34 @@
+// Package v1 exposes the querier's discovered StoreAPI endpoints over
+// HTTP at /api/v1/stores: their current health, advertised time ranges
+// and label sets, and recent health history, backing the querier web
+// UI's stores page.
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/thanos-io/thanos/pkg/query"
+)
+
+type API struct {
+	storeSet *query.StoreSet
+}
+
+func NewAPI(storeSet *query.StoreSet) *API {
+	return &API{storeSet: storeSet}
+}
+
+// Stores handles GET /api/v1/stores.
+func (a *API) Stores(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, a.storeSet.Statuses())
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	type envelope struct {
+		Status string      `json:"status"`
+		Data   interface{} `json:"data"`
+	}
+	_ = json.NewEncoder(w).Encode(envelope{Status: "success", Data: v})
+}
@@ -0,0 +1,76 @@
+package block
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/oklog/ulid"
+
+	"github.com/thanos-io/thanos/pkg/block/metadata"
+	"github.com/thanos-io/thanos/pkg/objstore"
+)
+
+// MetaFetcher lists and parses every block's meta.json in a bucket. It is
+// the shared starting point for the `thanos tools bucket` subcommands,
+// which all need the same block inventory before doing their own
+// replicate/rewrite/verify/inspect-specific work.
+type MetaFetcher struct {
+	bkt objstore.BucketReader
+}
+
+func NewMetaFetcher(bkt objstore.BucketReader) *MetaFetcher {
+	return &MetaFetcher{bkt: bkt}
+}
+
+// Fetch returns every block's parsed meta.json, keyed by block ULID. Blocks
+// whose meta.json is missing or unparsable are returned separately rather
+// than failing the whole fetch, since a single corrupt upload shouldn't
+// block tooling from seeing the rest of the bucket.
+func (f *MetaFetcher) Fetch(ctx context.Context) (metas map[ulid.ULID]*metadata.Meta, partial map[ulid.ULID]error, err error) {
+	metas = map[ulid.ULID]*metadata.Meta{}
+	partial = map[ulid.ULID]error{}
+
+	err = f.bkt.Iter(ctx, "", func(name string) error {
+		id, ok := parseBlockDir(name)
+		if !ok {
+			return nil
+		}
+
+		rc, err := f.bkt.Get(ctx, name+metadata.MetaFilename)
+		if err != nil {
+			if f.bkt.IsObjNotFoundErr(err) {
+				partial[id] = fmt.Errorf("missing %s", metadata.MetaFilename)
+				return nil
+			}
+			return fmt.Errorf("getting %s for block %s: %w", metadata.MetaFilename, id, err)
+		}
+		defer rc.Close()
+
+		m, err := metadata.ParseMeta(rc)
+		if err != nil {
+			partial[id] = err
+			return nil
+		}
+		metas[id] = m
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("iterating bucket: %w", err)
+	}
+	return metas, partial, nil
+}
+
+// parseBlockDir reports whether name looks like a top-level block directory
+// ("<ulid>/") and, if so, returns its ULID.
+func parseBlockDir(name string) (ulid.ULID, bool) {
+	name = strings.TrimSuffix(name, "/")
+	if strings.Contains(name, "/") {
+		return ulid.ULID{}, false
+	}
+	id, err := ulid.Parse(name)
+	if err != nil {
+		return ulid.ULID{}, false
+	}
+	return id, true
+}
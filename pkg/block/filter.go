@@ -0,0 +1,43 @@
+package block
+
+import (
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/thanos-io/thanos/pkg/block/metadata"
+)
+
+// Selector picks which blocks a `thanos tools bucket` subcommand should
+// operate on, shared across replicate/ls/inspect/verify so that block
+// selection semantics (and their flag names) stay consistent.
+type Selector struct {
+	Matchers   []*labels.Matcher
+	MinTime    int64 // inclusive, milliseconds; 0 means unbounded.
+	MaxTime    int64 // exclusive, milliseconds; 0 means unbounded.
+	Resolutions map[int64]struct{} // empty means all resolutions.
+}
+
+// Matches reports whether m's external labels, time range and downsampling
+// resolution satisfy s.
+func (s Selector) Matches(m *metadata.Meta) bool {
+	if s.MinTime != 0 && m.MaxTime <= s.MinTime {
+		return false
+	}
+	if s.MaxTime != 0 && m.MinTime >= s.MaxTime {
+		return false
+	}
+	if len(s.Resolutions) > 0 {
+		if _, ok := s.Resolutions[m.Thanos.Downsample.Resolution]; !ok {
+			return false
+		}
+	}
+	if len(s.Matchers) == 0 {
+		return true
+	}
+	set := labels.FromMap(m.Thanos.Labels)
+	for _, matcher := range s.Matchers {
+		if !matcher.Matches(set.Get(matcher.Name)) {
+			return false
+		}
+	}
+	return true
+}
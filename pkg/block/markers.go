@@ -0,0 +1,86 @@
+package block
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/oklog/ulid"
+
+	"github.com/thanos-io/thanos/pkg/objstore"
+)
+
+const (
+	// DeletionMarkFilename marks a block for deletion by the compactor's
+	// cleanup sweep after DeleteDelay has elapsed.
+	DeletionMarkFilename = "deletion-mark.json"
+	// NoCompactMarkFilename excludes a block from compaction planning
+	// entirely, without affecting its eligibility for querying.
+	NoCompactMarkFilename = "no-compact-mark.json"
+)
+
+// DeletionMark is uploaded alongside a block to schedule it for deletion.
+type DeletionMark struct {
+	ID      ulid.ULID `json:"id"`
+	Version int       `json:"version"`
+	// DeletionTime is a Unix timestamp set by the caller; this package
+	// never reads the clock itself so that mark creation stays
+	// deterministic and testable.
+	DeletionTime int64  `json:"time"`
+	Details      string `json:"details"`
+}
+
+// NoCompactMark is uploaded alongside a block to exclude it from
+// compaction planning.
+type NoCompactMark struct {
+	ID      ulid.ULID `json:"id"`
+	Version int       `json:"version"`
+	Time    int64      `json:"time"`
+	Details string     `json:"details"`
+}
+
+const markerVersion = 1
+
+// MarkForDeletion uploads a DeletionMark for id with the given details and
+// timestamp.
+func MarkForDeletion(ctx context.Context, bkt objstore.Bucket, id ulid.ULID, details string, deletionTime int64) error {
+	m := DeletionMark{ID: id, Version: markerVersion, DeletionTime: deletionTime, Details: details}
+	return uploadMark(ctx, bkt, id, DeletionMarkFilename, m)
+}
+
+// UnmarkForDeletion removes id's DeletionMark, if any.
+func UnmarkForDeletion(ctx context.Context, bkt objstore.Bucket, id ulid.ULID) error {
+	return removeMark(ctx, bkt, id, DeletionMarkFilename)
+}
+
+// MarkForNoCompact uploads a NoCompactMark for id with the given details.
+func MarkForNoCompact(ctx context.Context, bkt objstore.Bucket, id ulid.ULID, details string, markTime int64) error {
+	m := NoCompactMark{ID: id, Version: markerVersion, Time: markTime, Details: details}
+	return uploadMark(ctx, bkt, id, NoCompactMarkFilename, m)
+}
+
+// UnmarkForNoCompact removes id's NoCompactMark, if any.
+func UnmarkForNoCompact(ctx context.Context, bkt objstore.Bucket, id ulid.ULID) error {
+	return removeMark(ctx, bkt, id, NoCompactMarkFilename)
+}
+
+func uploadMark(ctx context.Context, bkt objstore.Bucket, id ulid.ULID, filename string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshaling %s for block %s: %w", filename, id, err)
+	}
+	return bkt.Upload(ctx, id.String()+"/"+filename, bytes.NewReader(b))
+}
+
+func removeMark(ctx context.Context, bkt objstore.Bucket, id ulid.ULID, filename string) error {
+	name := id.String() + "/" + filename
+	exists, err := bkt.Exists(ctx, name)
+	if err != nil {
+		return fmt.Errorf("checking %s for block %s: %w", filename, id, err)
+	}
+	if !exists {
+		return nil
+	}
+	return bkt.Delete(ctx, name)
+}
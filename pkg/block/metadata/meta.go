@@ -0,0 +1,59 @@
+// Package metadata holds the meta.json sidecar that Thanos writes next to
+// every TSDB block it owns in object storage.
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/oklog/ulid"
+	"github.com/prometheus/prometheus/tsdb"
+)
+
+// MetaFilename is the name meta.json is always written under, both locally
+// and in the bucket.
+const MetaFilename = "meta.json"
+
+// Meta extends tsdb.BlockMeta with the Thanos-specific fields persisted in
+// meta.json: external labels, downsampling resolution and source.
+type Meta struct {
+	tsdb.BlockMeta
+	ULID   ulid.ULID `json:"ulid"`
+	Thanos Thanos    `json:"thanos"`
+}
+
+// Thanos holds block metadata that is specific to Thanos and not carried by
+// the upstream TSDB BlockMeta.
+type Thanos struct {
+	Labels     map[string]string `json:"labels"`
+	Downsample struct {
+		Resolution int64 `json:"resolution"`
+	} `json:"downsample"`
+	Source string `json:"source"`
+}
+
+// ReadFromDir reads and parses the meta.json file under dir.
+func ReadFromDir(dir string) (*Meta, error) {
+	b, err := os.ReadFile(filepath.Join(dir, MetaFilename))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", MetaFilename, err)
+	}
+	var m Meta
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", MetaFilename, err)
+	}
+	return &m, nil
+}
+
+// ParseMeta parses a meta.json document read from r, e.g. fetched from
+// object storage rather than a local directory.
+func ParseMeta(r io.Reader) (*Meta, error) {
+	var m Meta
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", MetaFilename, err)
+	}
+	return &m, nil
+}
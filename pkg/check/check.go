@@ -0,0 +1,153 @@
+// Package check implements schema-aware validation for the config and rule
+// files Thanos components load at startup, so `thanos tools check` can
+// catch a typo in CI instead of it only surfacing when a process fails to
+// start.
+package check
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/prometheus/prometheus/promql/parser"
+	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/thanos-io/thanos/pkg/objstore/client"
+	"github.com/thanos-io/thanos/pkg/rule"
+	tracingclient "github.com/thanos-io/thanos/pkg/tracing/client"
+)
+
+// promqlParser is shared across this file's PromQL syntax checks; ParseExpr
+// and friends are methods on it rather than package-level functions.
+var promqlParser = parser.NewParser(parser.Options{})
+
+// ObjstoreConfig validates content against the objstore client config
+// schema. Strict decoding means a misspelled or unknown field is reported
+// by line number instead of being silently ignored.
+func ObjstoreConfig(content []byte) error {
+	var cfg client.BucketConfig
+	if err := yaml.UnmarshalStrict(content, &cfg); err != nil {
+		return fmt.Errorf("invalid objstore config: %w", err)
+	}
+	switch cfg.Type {
+	case client.OSS, client.BOS, client.FILESYSTEM:
+	default:
+		return fmt.Errorf("invalid objstore config: unknown type %q", cfg.Type)
+	}
+	if cfg.DryRun && !cfg.ReadOnly {
+		return fmt.Errorf("invalid objstore config: dry_run requires read_only")
+	}
+	return nil
+}
+
+// TracingConfig validates content against the tracing client config
+// schema.
+func TracingConfig(content []byte) error {
+	var cfg tracingclient.TracingConfig
+	if err := yaml.UnmarshalStrict(content, &cfg); err != nil {
+		return fmt.Errorf("invalid tracing config: %w", err)
+	}
+	if _, ok := tracingclient.KnownBackends[cfg.Type]; !ok {
+		return fmt.Errorf("invalid tracing config: unknown type %q", cfg.Type)
+	}
+	return nil
+}
+
+// RelabelConfig validates content as a list of Prometheus relabel rules.
+// relabel.Config's own UnmarshalYAML already rejects invalid actions,
+// regexes and label names, so this only needs to decode it.
+func RelabelConfig(content []byte) error {
+	var cfgs []*relabel.Config
+	if err := yaml.UnmarshalStrict(content, &cfgs); err != nil {
+		return fmt.Errorf("invalid relabel config: %w", err)
+	}
+	return nil
+}
+
+// RuleFile validates content as a Thanos ruler RuleGroupFile: every group
+// has a unique, non-empty name, every rule is exactly one of a recording
+// rule or an alert, and every expr parses as PromQL.
+func RuleFile(content []byte) error {
+	dec := yamlv3.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	var f rule.RuleGroupFile
+	if err := dec.Decode(&f); err != nil {
+		return fmt.Errorf("invalid rule file: %w", err)
+	}
+
+	seen := map[string]bool{}
+	for _, g := range f.Groups {
+		if g.Name == "" {
+			return fmt.Errorf("invalid rule file: group has no name")
+		}
+		if seen[g.Name] {
+			return fmt.Errorf("invalid rule file: duplicate group name %q", g.Name)
+		}
+		seen[g.Name] = true
+
+		for i, r := range g.Rules {
+			if (r.Record == "") == (r.Alert == "") {
+				return fmt.Errorf("invalid rule file: group %q rule %d must set exactly one of record or alert", g.Name, i)
+			}
+			if _, err := promqlParser.ParseExpr(r.Expr); err != nil {
+				return fmt.Errorf("invalid rule file: group %q rule %d: parsing expr: %w", g.Name, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// RulesUnitTestGroup is one `tests:` entry of a promtool-style rule unit
+// test file: a PromQL expression to evaluate at input_series' given
+// points, checked against exp_samples.
+type RulesUnitTestGroup struct {
+	Interval    string   `yaml:"interval"`
+	InputSeries []struct {
+		Series string `yaml:"series"`
+		Values string `yaml:"values"`
+	} `yaml:"input_series"`
+	PromqlExprTest []struct {
+		Expr       string `yaml:"expr"`
+		EvalTime   string `yaml:"eval_time"`
+		ExpSamples []struct {
+			Labels string `yaml:"labels"`
+			Value  float64 `yaml:"value"`
+		} `yaml:"exp_samples"`
+	} `yaml:"promql_expr_test"`
+}
+
+// RulesUnitTestFile is the schema of a promtool-style rule unit test file.
+type RulesUnitTestFile struct {
+	RuleFiles          []string             `yaml:"rule_files"`
+	EvaluationInterval string               `yaml:"evaluation_interval"`
+	Tests              []RulesUnitTestGroup `yaml:"tests"`
+}
+
+// RulesUnitTest validates content against the promtool-style rule unit
+// test file schema, checking that every input series label set and every
+// test's expr parse. Actually evaluating a test (running each expr against
+// its input_series at eval_time and diffing exp_samples) requires a PromQL
+// engine this tree doesn't carry; see pkg/store.ChunkIterable's doc
+// comment for the matching gap on the storage side.
+func RulesUnitTest(content []byte) error {
+	dec := yamlv3.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	var f RulesUnitTestFile
+	if err := dec.Decode(&f); err != nil {
+		return fmt.Errorf("invalid rule unit test file: %w", err)
+	}
+	for i, group := range f.Tests {
+		for j, s := range group.InputSeries {
+			if _, err := promqlParser.ParseMetric(s.Series); err != nil {
+				return fmt.Errorf("invalid rule unit test file: test %d input_series %d: parsing series: %w", i, j, err)
+			}
+		}
+		for j, t := range group.PromqlExprTest {
+			if _, err := promqlParser.ParseExpr(t.Expr); err != nil {
+				return fmt.Errorf("invalid rule unit test file: test %d promql_expr_test %d: parsing expr: %w", i, j, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Package cleanup runs the marked-block deletion and partial-upload
+// cleanup sweep against a bucket, using the same delete-delay semantics as
+// the compactor, so a single cleanup owner can run it standalone when the
+// compactor itself is sharded.
+package cleanup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid"
+
+	"github.com/thanos-io/thanos/pkg/block"
+	"github.com/thanos-io/thanos/pkg/block/metadata"
+	"github.com/thanos-io/thanos/pkg/objstore"
+)
+
+// Config configures a single cleanup pass.
+type Config struct {
+	// DeleteDelay is how long a block must have carried a deletion mark
+	// before it is actually removed, giving in-flight queriers/store
+	// gateways time to finish using it.
+	DeleteDelay time.Duration
+	// PartialUploadThreshold: a block directory with no meta.json older
+	// than this is considered an abandoned partial upload and removed
+	// entirely.
+	PartialUploadThreshold time.Duration
+}
+
+// Cleaner runs deletion-mark sweeps and partial-upload cleanup against a
+// bucket.
+type Cleaner struct {
+	logger log.Logger
+	bkt    objstore.Bucket
+	cfg    Config
+}
+
+func New(logger log.Logger, bkt objstore.Bucket, cfg Config) *Cleaner {
+	return &Cleaner{logger: logger, bkt: bkt, cfg: cfg}
+}
+
+// Run performs one cleanup pass as of now.
+func (c *Cleaner) Run(ctx context.Context, now time.Time) error {
+	if err := c.deleteMarkedBlocks(ctx, now); err != nil {
+		return fmt.Errorf("deleting marked blocks: %w", err)
+	}
+	if err := c.deletePartialUploads(ctx, now); err != nil {
+		return fmt.Errorf("deleting partial uploads: %w", err)
+	}
+	return nil
+}
+
+func (c *Cleaner) deleteMarkedBlocks(ctx context.Context, now time.Time) error {
+	return c.bkt.Iter(ctx, "", func(name string) error {
+		id, ok := blockID(name)
+		if !ok {
+			return nil
+		}
+
+		markName := name + block.DeletionMarkFilename
+		rc, err := c.bkt.Get(ctx, markName)
+		if err != nil {
+			if c.bkt.IsObjNotFoundErr(err) {
+				return nil
+			}
+			return fmt.Errorf("getting %s for block %s: %w", block.DeletionMarkFilename, id, err)
+		}
+		defer rc.Close()
+
+		var mark block.DeletionMark
+		if err := json.NewDecoder(rc).Decode(&mark); err != nil {
+			return fmt.Errorf("parsing %s for block %s: %w", block.DeletionMarkFilename, id, err)
+		}
+
+		markedAt := time.Unix(mark.DeletionTime, 0)
+		if now.Sub(markedAt) < c.cfg.DeleteDelay {
+			return nil
+		}
+
+		if err := deleteDir(ctx, c.bkt, name); err != nil {
+			return fmt.Errorf("deleting block %s: %w", id, err)
+		}
+		level.Info(c.logger).Log("msg", "deleted block past delete delay", "block", id, "marked_at", markedAt)
+		return nil
+	})
+}
+
+func (c *Cleaner) deletePartialUploads(ctx context.Context, now time.Time) error {
+	return c.bkt.IterWithAttributes(ctx, "", func(name string, _ objstore.ObjectAttributes) error {
+		id, ok := blockID(name)
+		if !ok {
+			return nil
+		}
+
+		metaName := name + metadata.MetaFilename
+		exists, err := c.bkt.Exists(ctx, metaName)
+		if err != nil {
+			return fmt.Errorf("checking %s for block %s: %w", metadata.MetaFilename, id, err)
+		}
+		if exists {
+			return nil
+		}
+
+		// No meta.json means the upload never completed (the shipper
+		// always uploads meta.json last). Use the block directory's own
+		// age, via its ULID timestamp, rather than trusting any one
+		// file's attributes.
+		age := now.Sub(ulid.Time(id.Time()))
+		if age < c.cfg.PartialUploadThreshold {
+			return nil
+		}
+
+		if err := deleteDir(ctx, c.bkt, name); err != nil {
+			return fmt.Errorf("deleting partial upload %s: %w", id, err)
+		}
+		level.Info(c.logger).Log("msg", "deleted stale partial upload", "block", id, "age", age)
+		return nil
+	})
+}
+
+func deleteDir(ctx context.Context, bkt objstore.Bucket, dir string) error {
+	return bkt.Iter(ctx, dir, func(name string) error {
+		if name == dir {
+			return nil
+		}
+		return bkt.Delete(ctx, name)
+	})
+}
+
+func blockID(name string) (ulid.ULID, bool) {
+	trimmed := name
+	if len(trimmed) > 0 && trimmed[len(trimmed)-1] == '/' {
+		trimmed = trimmed[:len(trimmed)-1]
+	}
+	id, err := ulid.Parse(trimmed)
+	if err != nil {
+		return ulid.ULID{}, false
+	}
+	return id, true
+}
@@ -0,0 +1,56 @@
+// Package downsample implements Thanos' block downsampling: aggregating
+// raw or already-downsampled chunks into coarser 5m/1h resolution blocks
+// so long-range queries don't have to scan every raw sample.
+package downsample
+
+// AggrType identifies one of the aggregates downsampling computes per
+// series per window.
+type AggrType int
+
+const (
+	AggrCount AggrType = iota
+	AggrSum
+	AggrMin
+	AggrMax
+	AggrCounter
+)
+
+// CounterResetHint records whether a counter genuinely reset somewhere
+// inside one AggrCounter window, so the querier-side iterator doesn't
+// have to guess from a visible decrease alone. A counter's value can dip
+// at a window boundary purely because downsampling stores the window's
+// last raw value rather than a cumulative total relative to the previous
+// window — that dip isn't a reset, and compensating for it as one
+// fabricates a rate() spike that never happened. A genuine mid-window
+// reset produces the same shape in the aggregate, so without this hint
+// the two are indistinguishable after the fact.
+type CounterResetHint int
+
+const (
+	// CounterResetUnknown means no hint was computed for this window; the
+	// iterator falls back to inferring a reset from any decrease, the
+	// behavior before this hint existed.
+	CounterResetUnknown CounterResetHint = iota
+	// CounterResetNo means every decrease visible in this window's
+	// AggrCounter value relative to its own raw samples is a downsampling
+	// artifact, not a real reset.
+	CounterResetNo
+	// CounterResetYes means the counter genuinely reset at least once
+	// among the raw samples that fed this window.
+	CounterResetYes
+)
+
+// DetectCounterReset computes the hint a downsampler should store
+// alongside one window's AggrCounter value, given that window's raw
+// sample values in timestamp order.
+func DetectCounterReset(raw []float64) CounterResetHint {
+	if len(raw) == 0 {
+		return CounterResetUnknown
+	}
+	for i := 1; i < len(raw); i++ {
+		if raw[i] < raw[i-1] {
+			return CounterResetYes
+		}
+	}
+	return CounterResetNo
+}
@@ -0,0 +1,46 @@
+package downsample
+
+// CounterSeriesIterator walks a series of downsampled AggrCounter window
+// values, compensating for resets between windows using each window's
+// CounterResetHint rather than inferring one from every decrease: a
+// decrease whose window is hinted CounterResetNo is a boundary artifact
+// and left alone, since the following window's stored value is already
+// the true cumulative count; one hinted CounterResetYes (or Unknown, for
+// data downsampled before this hint existed) is compensated the
+// traditional way, by carrying the pre-reset value forward as an offset.
+type CounterSeriesIterator struct {
+	values []float64
+	hints  []CounterResetHint
+	i      int
+	offset float64
+}
+
+// NewCounterSeriesIterator returns an iterator over values, one per
+// window, using the matching CounterResetHint from hints for each. len(hints)
+// may be shorter than len(values); missing entries are treated as
+// CounterResetUnknown.
+func NewCounterSeriesIterator(values []float64, hints []CounterResetHint) *CounterSeriesIterator {
+	return &CounterSeriesIterator{values: values, hints: hints, i: -1}
+}
+
+// Next advances to the next window, returning false once exhausted.
+func (it *CounterSeriesIterator) Next() bool {
+	it.i++
+	if it.i > 0 && it.i < len(it.values) && it.values[it.i] < it.values[it.i-1] && it.hint(it.i) != CounterResetNo {
+		it.offset += it.values[it.i-1]
+	}
+	return it.i < len(it.values)
+}
+
+// At returns the current window's value, compensated for any reset found
+// at or before it.
+func (it *CounterSeriesIterator) At() float64 {
+	return it.values[it.i] + it.offset
+}
+
+func (it *CounterSeriesIterator) hint(i int) CounterResetHint {
+	if i < 0 || i >= len(it.hints) {
+		return CounterResetUnknown
+	}
+	return it.hints[i]
+}
@@ -0,0 +1,98 @@
+// Package compact annotates a block inventory with why each block is or
+// isn't eligible for compaction, so the compactor's loaded/global bucket
+// views can answer "why isn't this block compacted yet" without an
+// operator having to cross-reference markers and filters by hand.
+package compact
+
+import (
+	"time"
+
+	"github.com/oklog/ulid"
+
+	"github.com/thanos-io/thanos/pkg/block"
+	"github.com/thanos-io/thanos/pkg/block/metadata"
+)
+
+// Status is why a block is, or isn't, eligible for compaction right now.
+type Status string
+
+const (
+	// StatusFilteredOut means the block doesn't match the compactor's own
+	// block selector (e.g. a relabel-config exclusion), so it's never
+	// considered for planning at all.
+	StatusFilteredOut Status = "filtered"
+	// StatusMarkedForDeletion means the block carries a deletion-mark and
+	// DeleteDelay has already elapsed; the cleanup sweep will remove it
+	// rather than the compactor ever touching it.
+	StatusMarkedForDeletion Status = "marked-for-deletion"
+	// StatusPendingDeleteDelay means the block carries a deletion-mark but
+	// DeleteDelay hasn't elapsed yet, so it's still compactable until then.
+	StatusPendingDeleteDelay Status = "pending-delete-delay"
+	// StatusNoCompact means the block carries a no-compact-mark and is
+	// permanently excluded from planning.
+	StatusNoCompact Status = "no-compact"
+	// StatusPlanned means the block is part of the next compaction plan.
+	StatusPlanned Status = "planned"
+	// StatusEligible means the block is otherwise compactable but wasn't
+	// selected by the current plan.
+	StatusEligible Status = "eligible"
+)
+
+// BlockStatus is one block's planning status, with enough detail for a UI
+// to render a human-readable reason.
+type BlockStatus struct {
+	ULID    ulid.ULID `json:"ulid"`
+	Status  Status    `json:"status"`
+	Details string    `json:"details,omitempty"`
+}
+
+// Annotate computes each block in metas' Status. deletionMarks and
+// noCompactMarks hold the markers found alongside each block (the caller
+// fetches these, since reading them is itself a bucket round trip).
+// planned holds the ids selected by the most recent compaction plan, and
+// deleteDelay/now determine whether a deletion-marked block has actually
+// crossed its delay yet.
+func Annotate(
+	metas map[ulid.ULID]*metadata.Meta,
+	sel *block.Selector,
+	deletionMarks map[ulid.ULID]*block.DeletionMark,
+	noCompactMarks map[ulid.ULID]*block.NoCompactMark,
+	planned map[ulid.ULID]struct{},
+	deleteDelay time.Duration,
+	now time.Time,
+) []BlockStatus {
+	statuses := make([]BlockStatus, 0, len(metas))
+	for id, m := range metas {
+		statuses = append(statuses, annotateOne(id, m, sel, deletionMarks[id], noCompactMarks[id], planned, deleteDelay, now))
+	}
+	return statuses
+}
+
+func annotateOne(
+	id ulid.ULID,
+	m *metadata.Meta,
+	sel *block.Selector,
+	deletionMark *block.DeletionMark,
+	noCompactMark *block.NoCompactMark,
+	planned map[ulid.ULID]struct{},
+	deleteDelay time.Duration,
+	now time.Time,
+) BlockStatus {
+	if deletionMark != nil {
+		markedAt := time.Unix(deletionMark.DeletionTime, 0)
+		if now.Sub(markedAt) >= deleteDelay {
+			return BlockStatus{ULID: id, Status: StatusMarkedForDeletion, Details: deletionMark.Details}
+		}
+		return BlockStatus{ULID: id, Status: StatusPendingDeleteDelay, Details: "deletes at " + markedAt.Add(deleteDelay).String()}
+	}
+	if noCompactMark != nil {
+		return BlockStatus{ULID: id, Status: StatusNoCompact, Details: noCompactMark.Details}
+	}
+	if sel != nil && !sel.Matches(m) {
+		return BlockStatus{ULID: id, Status: StatusFilteredOut, Details: "excluded by selector"}
+	}
+	if _, ok := planned[id]; ok {
+		return BlockStatus{ULID: id, Status: StatusPlanned}
+	}
+	return BlockStatus{ULID: id, Status: StatusEligible}
+}
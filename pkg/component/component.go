@@ -0,0 +1,43 @@
+// Package component flags what Thanos component a process, or a StoreAPI, is.
+package component
+
+// Component is a generic component interface.
+type Component interface {
+	String() string
+}
+
+// StoreAPI is a component that implements the Thanos StoreAPI.
+type StoreAPI interface {
+	Component
+	ToProxyLabel() string
+}
+
+type component struct {
+	name string
+}
+
+func (c component) String() string {
+	return c.name
+}
+
+type storeAPI struct {
+	component
+}
+
+func (s storeAPI) ToProxyLabel() string {
+	return s.name
+}
+
+func newStoreAPI(name string) storeAPI {
+	return storeAPI{component{name: name}}
+}
+
+var (
+	Query       = newStoreAPI("query")
+	Sidecar     = newStoreAPI("sidecar")
+	Store       = newStoreAPI("store")
+	Receive     = newStoreAPI("receive")
+	Rule        = newStoreAPI("rule")
+	Compact     = component{name: "compact"}
+	QueryFrontend = component{name: "query-frontend"}
+)
@@ -0,0 +1,20 @@
+package dedup
+
+import "github.com/thanos-io/thanos/pkg/store/storepb"
+
+// GroupForDedup partitions series sources into those that still need this
+// querier's own replica-label dedup pass (leaf StoreAPIs: sidecars, store
+// gateways, receivers) and those that have already been deduplicated by a
+// downstream querier and must be passed through untouched, so a
+// querier-behind-querier topology doesn't double-penalize or drop samples
+// by re-running dedup on already-merged series.
+func GroupForDedup(storeTypes []string) (needsDedup, alreadyDeduped []int) {
+	for i, t := range storeTypes {
+		if t == storepb.StoreTypeQuerier {
+			alreadyDeduped = append(alreadyDeduped, i)
+		} else {
+			needsDedup = append(needsDedup, i)
+		}
+	}
+	return needsDedup, alreadyDeduped
+}
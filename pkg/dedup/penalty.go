@@ -0,0 +1,85 @@
+// Package dedup merges multiple replicas of the same series (distinguished
+// only by a replica label) into one, penalizing replicas that look "behind"
+// so that gaps in one replica are filled from another instead of producing
+// visible dips.
+package dedup
+
+// Sample is a single (timestamp, value) pair from one replica of a series.
+type Sample struct {
+	T int64
+	V float64
+}
+
+// PenaltyDedup merges samples from multiple replicas of the same series,
+// preferring whichever replica's value is ahead at each timestamp, and
+// correctly handling counters: a replica whose value *decreases* relative to
+// its own last sample is assumed to have reset (a process restart), and its
+// subsequent values are treated as resuming from zero rather than being
+// penalized as "behind", avoiding the spurious dips earlier penalty-based
+// dedup produced across a counter reset.
+func PenaltyDedup(replicas [][]Sample, isCounter bool) []Sample {
+	if len(replicas) == 0 {
+		return nil
+	}
+	if len(replicas) == 1 {
+		return replicas[0]
+	}
+
+	lastSeen := make([]float64, len(replicas))
+	offset := make([]float64, len(replicas))
+	idx := make([]int, len(replicas))
+	var out []Sample
+
+	for {
+		t, ok := nextTimestamp(replicas, idx)
+		if !ok {
+			break
+		}
+
+		bestV, havePick := 0.0, false
+		for r, series := range replicas {
+			if idx[r] >= len(series) || series[idx[r]].T != t {
+				continue
+			}
+			v := series[idx[r]].V
+			if isCounter {
+				if v+offset[r] < lastSeen[r] {
+					// Counter reset on this replica: resume counting from
+					// zero instead of penalizing the dip. lastSeen[r]
+					// already has every prior offset folded in, so the new
+					// offset is exactly lastSeen[r], not an addition to the
+					// old offset — adding would double-count every reset
+					// after the first.
+					offset[r] = lastSeen[r]
+				}
+				lastSeen[r] = v + offset[r]
+				v = lastSeen[r]
+			}
+			if !havePick || v > bestV {
+				bestV, havePick = v, true
+			}
+			idx[r]++
+		}
+		if havePick {
+			out = append(out, Sample{T: t, V: bestV})
+		}
+	}
+	return out
+}
+
+func nextTimestamp(replicas [][]Sample, idx []int) (int64, bool) {
+	var (
+		min int64
+		has bool
+	)
+	for r, series := range replicas {
+		if idx[r] >= len(series) {
+			continue
+		}
+		t := series[idx[r]].T
+		if !has || t < min {
+			min, has = t, true
+		}
+	}
+	return min, has
+}
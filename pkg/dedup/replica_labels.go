@@ -0,0 +1,46 @@
+package dedup
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// ReplicaLabelSets holds the configured replica label names. More
+// than one label can identify a replica (e.g. both "replica" and
+// "rule_replica" in a federated setup), and any of them present on a series
+// triggers dedup against series that otherwise share all remaining labels.
+type ReplicaLabelSets []string
+
+// StripReplicaLabels returns lset with every configured replica label
+// removed; the result is what series from different replicas are grouped
+// by for dedup.
+func (s ReplicaLabelSets) StripReplicaLabels(lset labels.Labels) labels.Labels {
+	if len(s) == 0 {
+		return lset
+	}
+	b := labels.NewBuilder(lset)
+	for _, name := range s {
+		b.Del(name)
+	}
+	return b.Labels()
+}
+
+// ParamName is the query parameter letting a single request override the
+// server-configured replica label set, e.g. for a caller that wants to see
+// un-deduplicated per-replica series.
+const ParamName = "replicaLabels[]"
+
+// FromRequest returns the effective replica label set for r: the request's
+// override if present (even if empty, meaning "no dedup"), otherwise
+// defaults.
+func FromRequest(r *http.Request, defaults ReplicaLabelSets) ReplicaLabelSets {
+	if _, ok := r.Form[ParamName]; ok {
+		return ReplicaLabelSets(r.Form[ParamName])
+	}
+	if v := r.FormValue("replicaLabels"); v != "" {
+		return ReplicaLabelSets(strings.Split(v, ","))
+	}
+	return defaults
+}
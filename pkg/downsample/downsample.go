@@ -0,0 +1,186 @@
+// Package downsample plans and drives a standalone downsampling backlog
+// drain: given a set of blocks, it decides which ones are missing their
+// next downsampling level and processes them with bounded concurrency,
+// persisting enough progress in the bucket itself to resume a killed run
+// without redoing finished blocks.
+package downsample
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid"
+
+	"github.com/thanos-io/thanos/pkg/block/metadata"
+	"github.com/thanos-io/thanos/pkg/objstore"
+)
+
+// Resolutions, in milliseconds, that a block progresses through. A raw
+// block is downsampled to ResolutionLevel1 once it leaves the raw
+// retention window, then to ResolutionLevel2.
+const (
+	ResolutionLevelRaw = int64(0)
+	ResolutionLevel1   = int64(5 * 60 * 1000)
+	ResolutionLevel2   = int64(60 * 60 * 1000)
+)
+
+// progressFilename records, per source block, the finest resolution a run
+// has already produced, so a restarted run can skip completed work.
+const progressFilename = "downsample/progress.json"
+
+// NextResolution returns the resolution a block currently at "from" should
+// be downsampled to next, and false once it's already at the coarsest
+// level.
+func NextResolution(from int64) (int64, bool) {
+	switch from {
+	case ResolutionLevelRaw:
+		return ResolutionLevel1, true
+	case ResolutionLevel1:
+		return ResolutionLevel2, true
+	default:
+		return 0, false
+	}
+}
+
+// Job is one source block queued for downsampling to Target.
+type Job struct {
+	Block  ulid.ULID
+	From   int64
+	Target int64
+}
+
+// Plan returns one Job per block in metas that still needs downsampling,
+// skipping anything progress already recorded as done, ordered by ULID so
+// repeated runs process blocks in a stable order.
+func Plan(metas map[ulid.ULID]*metadata.Meta, progress Progress) []Job {
+	var jobs []Job
+	for id, m := range metas {
+		target, ok := NextResolution(m.Thanos.Downsample.Resolution)
+		if !ok {
+			continue
+		}
+		if progress[id] >= target {
+			continue
+		}
+		jobs = append(jobs, Job{Block: id, From: m.Thanos.Downsample.Resolution, Target: target})
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Block.String() < jobs[j].Block.String() })
+	return jobs
+}
+
+// Progress maps a source block to the finest resolution a prior run has
+// already produced for it.
+type Progress map[ulid.ULID]int64
+
+// LoadProgress reads the progress record from bkt, returning an empty
+// Progress if none has been written yet.
+func LoadProgress(ctx context.Context, bkt objstore.Bucket) (Progress, error) {
+	rc, err := bkt.Get(ctx, progressFilename)
+	if err != nil {
+		if bkt.IsObjNotFoundErr(err) {
+			return Progress{}, nil
+		}
+		return nil, fmt.Errorf("getting downsample progress: %w", err)
+	}
+	defer rc.Close()
+
+	p := Progress{}
+	if err := json.NewDecoder(rc).Decode(&p); err != nil {
+		return nil, fmt.Errorf("parsing downsample progress: %w", err)
+	}
+	return p, nil
+}
+
+func saveProgress(ctx context.Context, bkt objstore.Bucket, p Progress) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("marshaling downsample progress: %w", err)
+	}
+	return bkt.Upload(ctx, progressFilename, bytes.NewReader(b))
+}
+
+// AggregateFunc produces the downsampled block for job and uploads it,
+// returning the new block's ULID. Implementations own the actual
+// chunk-level aggregation; this package only owns selection, concurrency
+// and resumability around whatever AggregateFunc does.
+type AggregateFunc func(ctx context.Context, bkt objstore.Bucket, job Job) (ulid.ULID, error)
+
+// Config configures a downsample run.
+type Config struct {
+	// Concurrency is the number of jobs processed at once. Values <= 0 are
+	// treated as 1.
+	Concurrency int
+}
+
+// Downsampler drains a backlog of Jobs against bkt with bounded
+// concurrency, persisting progress after each completed job so a killed
+// run can resume without reprocessing finished blocks.
+type Downsampler struct {
+	logger    log.Logger
+	bkt       objstore.Bucket
+	cfg       Config
+	aggregate AggregateFunc
+}
+
+func New(logger log.Logger, bkt objstore.Bucket, cfg Config, aggregate AggregateFunc) *Downsampler {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	return &Downsampler{logger: logger, bkt: bkt, cfg: cfg, aggregate: aggregate}
+}
+
+// Run processes every job in jobs with up to cfg.Concurrency workers; it
+// does not itself skip jobs that progress already records as done.
+// progress is mutated and persisted in place as jobs complete, so a caller
+// that re-plans with Plan(metas, progress) after a failure only repeats
+// the jobs that didn't finish.
+func (d *Downsampler) Run(ctx context.Context, jobs []Job, progress Progress) error {
+	var (
+		mu   sync.Mutex
+		sem  = make(chan struct{}, d.cfg.Concurrency)
+		wg   sync.WaitGroup
+		errs []error
+	)
+
+	for _, job := range jobs {
+		job := job
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			newID, err := d.aggregate(ctx, d.bkt, job)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("block %s: %w", job.Block, err))
+				mu.Unlock()
+				return
+			}
+
+			mu.Lock()
+			progress[job.Block] = job.Target
+			saveErr := saveProgress(ctx, d.bkt, progress)
+			mu.Unlock()
+			if saveErr != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("saving progress for block %s: %w", job.Block, saveErr))
+				mu.Unlock()
+				return
+			}
+			level.Info(d.logger).Log("msg", "downsampled block", "source", job.Block, "result", newID, "from", job.From, "to", job.Target)
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d of %d jobs failed, first error: %w", len(errs), len(jobs), errs[0])
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Package exemplars implements the Thanos ExemplarsAPI: the sidecar serves
+// it from its local Prometheus TSDB, and the querier fans a request out
+// across all exemplar-capable StoreAPIs and deduplicates by series, value
+// and timestamp.
+package exemplars
+
+import (
+	"context"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+// Sidecar implements storepb.ExemplarsServer by querying a local Prometheus
+// exemplar storage.ExemplarQuerier.
+type Sidecar struct {
+	queryable storage.ExemplarQueryable
+}
+
+func NewSidecar(queryable storage.ExemplarQueryable) *Sidecar {
+	return &Sidecar{queryable: queryable}
+}
+
+func (s *Sidecar) Exemplars(ctx context.Context, r *storepb.ExemplarsRequest) (*storepb.ExemplarsResponse, error) {
+	q, err := s.queryable.ExemplarQuerier(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := q.Select(r.Start, r.End, translateMatchers(r.Matchers))
+	if err != nil {
+		return nil, err
+	}
+	resp := &storepb.ExemplarsResponse{}
+	for _, er := range res {
+		data := storepb.ExemplarData{SeriesLabels: translateLabels(er.SeriesLabels)}
+		for _, ex := range er.Exemplars {
+			data.Exemplars = append(data.Exemplars, storepb.Exemplar{
+				Labels: translateLabels(ex.Labels),
+				Value:  ex.Value,
+				Ts:     ex.Ts,
+			})
+		}
+		resp.Data = append(resp.Data, data)
+	}
+	return resp, nil
+}
+
+// Proxy implements storepb.ExemplarsServer by fanning out to multiple
+// clients and deduplicating exemplars that share series labels, value and
+// timestamp (the same exemplar observed through more than one replica).
+type Proxy struct {
+	clients func() []storepb.ExemplarsClient
+}
+
+func NewProxy(clients func() []storepb.ExemplarsClient) *Proxy {
+	return &Proxy{clients: clients}
+}
+
+func (p *Proxy) Exemplars(ctx context.Context, r *storepb.ExemplarsRequest) (*storepb.ExemplarsResponse, error) {
+	type key struct {
+		series string
+		value  float64
+		ts     int64
+	}
+	seen := map[key]struct{}{}
+	bySeries := map[string]int{} // index into merged.Data, keyed by series label set
+	merged := &storepb.ExemplarsResponse{}
+
+	for _, c := range p.clients() {
+		resp, err := c.Exemplars(ctx, r)
+		if err != nil {
+			merged.Warnings = append(merged.Warnings, err.Error())
+			continue
+		}
+		for _, data := range resp.Data {
+			sk := seriesKey(data.SeriesLabels)
+			idx, ok := bySeries[sk]
+			if !ok {
+				merged.Data = append(merged.Data, storepb.ExemplarData{SeriesLabels: data.SeriesLabels})
+				idx = len(merged.Data) - 1
+				bySeries[sk] = idx
+			}
+			for _, ex := range data.Exemplars {
+				k := key{sk, ex.Value, ex.Ts}
+				if _, dup := seen[k]; dup {
+					continue
+				}
+				seen[k] = struct{}{}
+				merged.Data[idx].Exemplars = append(merged.Data[idx].Exemplars, ex)
+			}
+		}
+	}
+	return merged, nil
+}
+
+func seriesKey(lbls []storepb.Label) string {
+	s := ""
+	for _, l := range lbls {
+		s += l.Name + "=" + l.Value + ";"
+	}
+	return s
+}
+
+func translateMatchers(ms []storepb.LabelMatcher) []*labels.Matcher {
+	out := make([]*labels.Matcher, 0, len(ms))
+	for _, m := range ms {
+		var t labels.MatchType
+		switch m.Type {
+		case storepb.MatcherType_EQ:
+			t = labels.MatchEqual
+		case storepb.MatcherType_NEQ:
+			t = labels.MatchNotEqual
+		case storepb.MatcherType_RE:
+			t = labels.MatchRegexp
+		case storepb.MatcherType_NRE:
+			t = labels.MatchNotRegexp
+		}
+		out = append(out, labels.MustNewMatcher(t, m.Name, m.Value))
+	}
+	return out
+}
+
+func translateLabels(lbls labels.Labels) []storepb.Label {
+	out := make([]storepb.Label, 0, lbls.Len())
+	lbls.Range(func(l labels.Label) {
+		out = append(out, storepb.Label{Name: l.Name, Value: l.Value})
+	})
+	return out
+}
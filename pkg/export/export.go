@@ -0,0 +1,178 @@
+// Package export streams samples selected from bucket blocks into
+// row-oriented CSV or Parquet files for offline analytics tooling such as
+// Spark or BigQuery, which want flat rows rather than a TSDB's native
+// chunk-encoded columnar layout.
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/oklog/ulid"
+	"github.com/parquet-go/parquet-go"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/thanos-io/thanos/pkg/block"
+)
+
+// Row is one exported sample, flattened so every row has the same schema
+// regardless of which series it came from.
+type Row struct {
+	Block      string  `parquet:"block" csv:"block"`
+	Labels     string  `parquet:"labels" csv:"labels"`
+	Resolution int64   `parquet:"resolution" csv:"resolution"`
+	Timestamp  int64   `parquet:"timestamp" csv:"timestamp"`
+	Value      float64 `parquet:"value" csv:"value"`
+}
+
+// Sample is one (timestamp, value) pair of a series.
+type Sample struct {
+	Timestamp int64
+	Value     float64
+}
+
+// SeriesSource yields every series matching sel in block id, along with its
+// samples. Reading samples out of a block's chunks requires decoding them
+// via tsdb/chunkenc, which belongs to a local block reader this tree
+// doesn't carry; Export only depends on this interface so that seam can be
+// filled in independently of the row/file-format plumbing below.
+type SeriesSource interface {
+	ForEach(ctx context.Context, id ulid.ULID, sel block.Selector, f func(lset labels.Labels, resolution int64, samples []Sample) error) error
+}
+
+// RowWriter writes Rows to a single output file.
+type RowWriter interface {
+	WriteRow(Row) error
+	Close() error
+}
+
+// Export drains src for every block in ids and writes one Row per sample
+// via w.
+func Export(ctx context.Context, src SeriesSource, ids []ulid.ULID, sel block.Selector, w RowWriter) error {
+	for _, id := range ids {
+		err := src.ForEach(ctx, id, sel, func(lset labels.Labels, resolution int64, samples []Sample) error {
+			for _, s := range samples {
+				if err := w.WriteRow(Row{
+					Block:      id.String(),
+					Labels:     lset.String(),
+					Resolution: resolution,
+					Timestamp:  s.Timestamp,
+					Value:      s.Value,
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("exporting block %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+type csvWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewCSVWriter returns a RowWriter that writes a header row followed by one
+// row per sample.
+func NewCSVWriter(w io.Writer) RowWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteRow(r Row) error {
+	if !c.wroteHeader {
+		if err := c.w.Write([]string{"block", "labels", "resolution", "timestamp", "value"}); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+	return c.w.Write([]string{
+		r.Block,
+		r.Labels,
+		strconv.FormatInt(r.Resolution, 10),
+		strconv.FormatInt(r.Timestamp, 10),
+		strconv.FormatFloat(r.Value, 'g', -1, 64),
+	})
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type parquetWriter struct {
+	w *parquet.GenericWriter[Row]
+}
+
+// NewParquetWriter returns a RowWriter backed by a Parquet file with one
+// row group flushed on Close.
+func NewParquetWriter(w io.Writer) RowWriter {
+	return &parquetWriter{w: parquet.NewGenericWriter[Row](w)}
+}
+
+func (p *parquetWriter) WriteRow(r Row) error {
+	_, err := p.w.Write([]Row{r})
+	return err
+}
+
+func (p *parquetWriter) Close() error {
+	return p.w.Close()
+}
+
+// ChunkedWriter wraps a RowWriter factory so output is split across
+// multiple files once maxRowsPerFile is reached, which keeps any single
+// exported file within what downstream Spark/BigQuery loaders expect.
+type ChunkedWriter struct {
+	newWriter      func(index int) (RowWriter, error)
+	maxRowsPerFile int
+
+	index   int
+	current RowWriter
+	rows    int
+}
+
+func NewChunkedWriter(maxRowsPerFile int, newWriter func(index int) (RowWriter, error)) *ChunkedWriter {
+	return &ChunkedWriter{newWriter: newWriter, maxRowsPerFile: maxRowsPerFile}
+}
+
+func (c *ChunkedWriter) WriteRow(r Row) error {
+	if c.current == nil || (c.maxRowsPerFile > 0 && c.rows >= c.maxRowsPerFile) {
+		if err := c.rotate(); err != nil {
+			return err
+		}
+	}
+	if err := c.current.WriteRow(r); err != nil {
+		return err
+	}
+	c.rows++
+	return nil
+}
+
+func (c *ChunkedWriter) rotate() error {
+	if c.current != nil {
+		if err := c.current.Close(); err != nil {
+			return fmt.Errorf("closing output file %d: %w", c.index, err)
+		}
+		c.index++
+	}
+	w, err := c.newWriter(c.index)
+	if err != nil {
+		return fmt.Errorf("creating output file %d: %w", c.index, err)
+	}
+	c.current = w
+	c.rows = 0
+	return nil
+}
+
+func (c *ChunkedWriter) Close() error {
+	if c.current == nil {
+		return nil
+	}
+	return c.current.Close()
+}
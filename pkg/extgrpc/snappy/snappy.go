@@ -0,0 +1,30 @@
+// Package snappy registers a gRPC "snappy" compressor with the global
+// google.golang.org/grpc/encoding registry so it can be selected with
+// grpc.UseCompressor(snappy.Name) on either end of a connection.
+package snappy
+
+import (
+	"io"
+
+	"github.com/golang/snappy"
+	"google.golang.org/grpc/encoding"
+)
+
+// Name is the name registered for the compressor.
+const Name = "snappy"
+
+func init() {
+	encoding.RegisterCompressor(compressor{})
+}
+
+type compressor struct{}
+
+func (compressor) Name() string { return Name }
+
+func (compressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
+
+func (compressor) Decompress(r io.Reader) (io.Reader, error) {
+	return snappy.NewReader(r), nil
+}
@@ -0,0 +1,86 @@
+// Package infopb defines the Info service: a single capability-advertisement
+// RPC every Thanos component implements, separate from any one data API.
+// storepb.InfoRequest/InfoResponse predate this package and only describe
+// Store capabilities; a component that also serves Rules, Targets,
+// Exemplars or Metadata had nowhere to advertise that short of a client
+// guessing and trying the call. Here, each *Info field on InfoResponse is
+// nil unless the component actually implements that API, so the querier
+// can route per-capability instead of assuming every StoreAPI endpoint
+// also speaks Rules or Exemplars.
+package infopb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+// InfoRequest requests capability advertisement from a component. Empty,
+// like storepb.InfoRequest: everything returned is either static or cheap
+// to compute on every call.
+type InfoRequest struct{}
+
+// InfoResponse describes every API a component implements. A nil *Info
+// field means the component doesn't implement that API at all; a non-nil
+// one, even if its fields are all zero values, means it does.
+type InfoResponse struct {
+	ComponentType string
+	LabelSets     [][]storepb.Label
+
+	Store          *StoreInfo
+	Rules          *RulesInfo
+	Targets        *TargetsInfo
+	MetricMetadata *MetricMetadataInfo
+	Exemplars      *ExemplarsInfo
+}
+
+// StoreInfo is the Store API's capability advertisement, superseding the
+// StoreType/MinTime/MaxTime/SupportsSortedSeries fields on the older
+// storepb.InfoResponse.
+type StoreInfo struct {
+	MinTime int64
+	MaxTime int64
+	// StoreType distinguishes a leaf store from a querier acting as a
+	// StoreAPI for another querier above it; see storepb.InfoResponse's
+	// StoreType doc comment for why that distinction matters to dedup.
+	StoreType string
+	// SupportsSortedSeries declares that Series responses are already
+	// label-sorted with external labels stripped; see
+	// storepb.InfoResponse.SupportsSortedSeries.
+	SupportsSortedSeries bool
+}
+
+// RulesInfo is the Rules API's capability advertisement. Empty today: a
+// component either implements Rules or it doesn't, and there's no
+// sub-capability within it yet worth advertising.
+type RulesInfo struct{}
+
+// TargetsInfo is the Targets API's capability advertisement.
+type TargetsInfo struct{}
+
+// MetricMetadataInfo is the Metadata API's capability advertisement.
+type MetricMetadataInfo struct{}
+
+// ExemplarsInfo is the Exemplars API's capability advertisement.
+type ExemplarsInfo struct {
+	MinTime int64
+	MaxTime int64
+}
+
+type InfoClient interface {
+	Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error)
+}
+
+type InfoServer interface {
+	Info(context.Context, *InfoRequest) (*InfoResponse, error)
+}
+
+// RegisterInfoServer registers srv as the handler for the Info service on
+// s. Omitted here since info.proto is not compiled in this tree; see
+// storepb.RegisterStoreServer for the same caveat.
+func RegisterInfoServer(s *grpc.Server, srv InfoServer) {
+	_ = s
+	_ = srv
+}
@@ -0,0 +1,141 @@
+// Package inspect formats block metadata for `thanos tools bucket inspect`.
+package inspect
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/oklog/ulid"
+
+	"github.com/thanos-io/thanos/pkg/block/metadata"
+)
+
+// Row is one block's worth of inspectable fields, flattened for
+// table/csv/json output.
+type Row struct {
+	ULID       ulid.ULID `json:"ulid"`
+	MinTime    int64     `json:"min_time"`
+	MaxTime    int64     `json:"max_time"`
+	Resolution int64     `json:"resolution"`
+	Series     uint64    `json:"series"`
+	Samples    uint64    `json:"samples"`
+	Source     string    `json:"source"`
+}
+
+func RowsFromMetas(metas map[ulid.ULID]*metadata.Meta) []Row {
+	rows := make([]Row, 0, len(metas))
+	for id, m := range metas {
+		rows = append(rows, Row{
+			ULID:       id,
+			MinTime:    m.MinTime,
+			MaxTime:    m.MaxTime,
+			Resolution: m.Thanos.Downsample.Resolution,
+			Series:     m.Stats.NumSeries,
+			Samples:    m.Stats.NumSamples,
+			Source:     m.Thanos.Source,
+		})
+	}
+	return rows
+}
+
+// columns maps a --sort-by name to the comparator it drives, so sort keys
+// stay in sync with what's actually printed.
+var columns = map[string]func(a, b Row) bool{
+	"ulid":       func(a, b Row) bool { return a.ULID.String() < b.ULID.String() },
+	"min-time":   func(a, b Row) bool { return a.MinTime < b.MinTime },
+	"max-time":   func(a, b Row) bool { return a.MaxTime < b.MaxTime },
+	"resolution": func(a, b Row) bool { return a.Resolution < b.Resolution },
+	"series":     func(a, b Row) bool { return a.Series < b.Series },
+	"samples":    func(a, b Row) bool { return a.Samples < b.Samples },
+}
+
+// SortBy sorts rows in place by the given column names, in priority order,
+// so `--sort-by=resolution,min-time` breaks ties on the second column.
+func SortBy(rows []Row, by []string) error {
+	var less []func(a, b Row) bool
+	for _, col := range by {
+		cmp, ok := columns[col]
+		if !ok {
+			return fmt.Errorf("unknown --sort-by column %q", col)
+		}
+		less = append(less, cmp)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, cmp := range less {
+			if cmp(rows[i], rows[j]) {
+				return true
+			}
+			if cmp(rows[j], rows[i]) {
+				return false
+			}
+		}
+		return false
+	})
+	return nil
+}
+
+// Write renders rows to w in the given format ("json", "csv" or "table").
+func Write(w io.Writer, format string, rows []Row) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case "csv":
+		return writeCSV(w, rows)
+	case "table", "":
+		return writeTable(w, rows)
+	default:
+		return fmt.Errorf("unknown --output format %q", format)
+	}
+}
+
+var header = []string{"ULID", "MIN TIME", "MAX TIME", "RESOLUTION", "SERIES", "SAMPLES", "SOURCE"}
+
+func rowStrings(r Row) []string {
+	return []string{
+		r.ULID.String(),
+		strconv.FormatInt(r.MinTime, 10),
+		strconv.FormatInt(r.MaxTime, 10),
+		strconv.FormatInt(r.Resolution, 10),
+		strconv.FormatUint(r.Series, 10),
+		strconv.FormatUint(r.Samples, 10),
+		r.Source,
+	}
+}
+
+func writeCSV(w io.Writer, rows []Row) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err := cw.Write(rowStrings(r)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeTable(w io.Writer, rows []Row) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, joinTab(header))
+	for _, r := range rows {
+		fmt.Fprintln(tw, joinTab(rowStrings(r)))
+	}
+	return tw.Flush()
+}
+
+func joinTab(cols []string) string {
+	out := cols[0]
+	for _, c := range cols[1:] {
+		out += "\t" + c
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package azure
+
+import (
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+// AuthConfig picks how the Azure Blob client authenticates. Exactly one of
+// StorageAccountKey, SASToken or MSI/AAD should be set.
+type AuthConfig struct {
+	StorageAccount    string `yaml:"storage_account"`
+	StorageAccountKey string `yaml:"storage_account_key"`
+	SASToken          string `yaml:"storage_sas_token"`
+
+	// UseManagedIdentity authenticates via the Azure Instance Metadata
+	// Service, with no secret ever touching Thanos' config, for workloads
+	// running on an Azure VM/AKS node with a managed identity assigned.
+	UseManagedIdentity bool   `yaml:"use_managed_identity"`
+	ManagedIdentityID  string `yaml:"managed_identity_client_id"`
+
+	// AAD client-credential (service principal) auth, for environments
+	// without managed identity (e.g. on-prem or multi-cloud).
+	TenantID     string `yaml:"tenant_id"`
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+}
+
+// TokenCredential builds an azcore.TokenCredential for cfg.
+func (cfg AuthConfig) TokenCredential() (azcore.TokenCredential, error) {
+	switch {
+	case cfg.UseManagedIdentity:
+		opts := &azidentity.ManagedIdentityCredentialOptions{}
+		if cfg.ManagedIdentityID != "" {
+			opts.ID = azidentity.ClientID(cfg.ManagedIdentityID)
+		}
+		cred, err := azidentity.NewManagedIdentityCredential(opts)
+		if err != nil {
+			return nil, fmt.Errorf("creating managed identity credential: %w", err)
+		}
+		return cred, nil
+	case cfg.ClientID != "" && cfg.ClientSecret != "" && cfg.TenantID != "":
+		cred, err := azidentity.NewClientSecretCredential(cfg.TenantID, cfg.ClientID, cfg.ClientSecret, nil)
+		if err != nil {
+			return nil, fmt.Errorf("creating AAD client secret credential: %w", err)
+		}
+		return cred, nil
+	default:
+		return nil, fmt.Errorf("no Azure AD credential configured; set use_managed_identity or tenant_id/client_id/client_secret")
+	}
+}
@@ -0,0 +1,21 @@
+package azure
+
+import (
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+
+	"github.com/thanos-io/thanos/pkg/objstore"
+)
+
+// UploadOptions translates a MultipartConfig into block blob upload
+// options, tuning block size and upload concurrency for large compactor
+// block segment files.
+func UploadOptions(cfg objstore.MultipartConfig) *blockblob.UploadStreamOptions {
+	opts := &blockblob.UploadStreamOptions{}
+	if cfg.PartSize > 0 {
+		opts.BlockSize = cfg.PartSize
+	}
+	if cfg.Concurrency > 0 {
+		opts.Concurrency = cfg.Concurrency
+	}
+	return opts
+}
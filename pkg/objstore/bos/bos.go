@@ -0,0 +1,132 @@
+// Package bos implements the objstore.Bucket interface against Baidu Cloud
+// Object Storage.
+package bos
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/baidubce/bce-sdk-go/bce"
+	"github.com/baidubce/bce-sdk-go/services/bos"
+	"github.com/baidubce/bce-sdk-go/services/bos/api"
+	"gopkg.in/yaml.v2"
+
+	"github.com/thanos-io/thanos/pkg/objstore"
+)
+
+// Config configures the BOS bucket client.
+type Config struct {
+	Endpoint  string `yaml:"endpoint"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+}
+
+func ParseConfig(content []byte) (Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(content, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parsing BOS config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Bucket implements objstore.Bucket for Baidu BOS.
+type Bucket struct {
+	name   string
+	client *bos.Client
+}
+
+func NewBucket(cfg Config) (*Bucket, error) {
+	client, err := bos.NewClient(cfg.AccessKey, cfg.SecretKey, cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("creating BOS client: %w", err)
+	}
+	return &Bucket{name: cfg.Bucket, client: client}, nil
+}
+
+func (b *Bucket) Name() string { return b.name }
+
+func (b *Bucket) Upload(ctx context.Context, name string, r io.Reader) error {
+	body, err := bce.NewBodyFromSizedReader(r, -1)
+	if err != nil {
+		return fmt.Errorf("preparing BOS upload body: %w", err)
+	}
+	_, err = b.client.PutObject(b.name, name, body, nil)
+	return err
+}
+
+func (b *Bucket) Delete(ctx context.Context, name string) error {
+	return b.client.DeleteObject(b.name, name)
+}
+
+func (b *Bucket) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	obj, err := b.client.BasicGetObject(b.name, name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.Body, nil
+}
+
+func (b *Bucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	obj, err := b.client.GetObject(b.name, name, nil, off, off+length-1)
+	if err != nil {
+		return nil, err
+	}
+	return obj.Body, nil
+}
+
+func (b *Bucket) Exists(ctx context.Context, name string) (bool, error) {
+	_, err := b.client.GetObjectMeta(b.name, name)
+	if err != nil {
+		if b.IsObjNotFoundErr(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (b *Bucket) IsObjNotFoundErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	bceErr, ok := err.(*bce.BceServiceError)
+	return ok && bceErr.StatusCode == 404
+}
+
+func (b *Bucket) Iter(ctx context.Context, dir string, f func(name string) error) error {
+	return b.IterWithAttributes(ctx, dir, func(name string, _ objstore.ObjectAttributes) error {
+		return f(name)
+	})
+}
+
+// IterWithAttributes reports size and last-modified time from the same
+// ListObjects page used for listing, avoiding a HEAD per object.
+func (b *Bucket) IterWithAttributes(ctx context.Context, dir string, f func(name string, attrs objstore.ObjectAttributes) error) error {
+	marker := ""
+	for {
+		args := &api.ListObjectsArgs{Prefix: dir, Delimiter: "/", Marker: marker}
+		res, err := b.client.ListObjects(b.name, args)
+		if err != nil {
+			return fmt.Errorf("listing BOS objects under %s: %w", dir, err)
+		}
+		for _, o := range res.Contents {
+			lastModified, _ := time.Parse(time.RFC3339, o.LastModified)
+			attrs := objstore.ObjectAttributes{Size: int64(o.Size), LastModified: lastModified}
+			if err := f(o.Key, attrs); err != nil {
+				return err
+			}
+		}
+		for _, p := range res.CommonPrefixes {
+			if err := f(p.Prefix, objstore.ObjectAttributes{}); err != nil {
+				return err
+			}
+		}
+		if !res.IsTruncated {
+			return nil
+		}
+		marker = res.NextMarker
+	}
+}
@@ -0,0 +1,96 @@
+// Package client provides a single entry point for turning a bucket YAML
+// config into an objstore.Bucket, regardless of provider.
+package client
+
+import (
+	"fmt"
+
+	"github.com/go-kit/log"
+	"gopkg.in/yaml.v2"
+
+	"github.com/thanos-io/thanos/pkg/objstore"
+	"github.com/thanos-io/thanos/pkg/objstore/bos"
+	"github.com/thanos-io/thanos/pkg/objstore/filesystem"
+	"github.com/thanos-io/thanos/pkg/objstore/oss"
+)
+
+// BucketType identifies a supported object storage provider.
+type BucketType string
+
+const (
+	OSS        BucketType = "OSS"
+	BOS        BucketType = "BOS"
+	FILESYSTEM BucketType = "FILESYSTEM"
+)
+
+// BucketConfig is the generic envelope every provider-specific config is
+// embedded in: `type` selects the provider and `config` is re-marshalled
+// into that provider's own Config struct.
+type BucketConfig struct {
+	Type BucketType `yaml:"type"`
+	// Prefix transparently scopes every operation under this key prefix, so
+	// multiple Thanos installations can share a single bucket. Applied
+	// uniformly across all providers by objstore.NewPrefixedBucket.
+	Prefix string        `yaml:"prefix"`
+	Config yaml.MapSlice `yaml:"config"`
+
+	// ReadOnly, if set, rejects every mutating operation against the
+	// resulting bucket. DryRun implies ReadOnly and logs
+	// mutating calls instead of erroring, so planning logic can be tested
+	// against a production bucket with no chance of a write.
+	ReadOnly bool `yaml:"read_only"`
+	DryRun   bool `yaml:"dry_run"`
+}
+
+// NewBucket parses confContentYaml and returns the resulting Bucket, with
+// Prefix and ReadOnly/DryRun applied if set.
+func NewBucket(logger log.Logger, confContentYaml []byte) (objstore.Bucket, error) {
+	var bucketConf BucketConfig
+	if err := yaml.Unmarshal(confContentYaml, &bucketConf); err != nil {
+		return nil, fmt.Errorf("parsing bucket config: %w", err)
+	}
+
+	config, err := yaml.Marshal(bucketConf.Config)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling bucket config for provider %s: %w", bucketConf.Type, err)
+	}
+
+	var bkt objstore.Bucket
+	switch bucketConf.Type {
+	case OSS:
+		cfg, err := oss.ParseConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		bkt, err = oss.NewBucket(cfg)
+		if err != nil {
+			return nil, err
+		}
+	case BOS:
+		cfg, err := bos.ParseConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		bkt, err = bos.NewBucket(cfg)
+		if err != nil {
+			return nil, err
+		}
+	case FILESYSTEM:
+		var cfg filesystem.Config
+		if err := yaml.Unmarshal(config, &cfg); err != nil {
+			return nil, fmt.Errorf("parsing filesystem config: %w", err)
+		}
+		bkt, err = filesystem.NewBucket(cfg)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported bucket type %q", bucketConf.Type)
+	}
+
+	bkt = objstore.NewPrefixedBucket(bkt, bucketConf.Prefix)
+	if bucketConf.ReadOnly || bucketConf.DryRun {
+		bkt = objstore.NewReadOnlyBucket(bkt, logger, bucketConf.DryRun)
+	}
+	return bkt, nil
+}
@@ -0,0 +1,230 @@
+// Package filesystem implements the objstore.Bucket interface against a
+// local (or NFS-mounted) directory. It is mainly useful for on-prem setups
+// and local development/testing.
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/thanos-io/thanos/pkg/objstore"
+)
+
+// Config configures the filesystem bucket client.
+type Config struct {
+	Directory string `yaml:"directory"`
+
+	// SyncOnUpload fsyncs both the temp file and its parent directory before
+	// the final rename, trading upload latency for durability against a
+	// crash immediately after Upload returns.
+	SyncOnUpload bool `yaml:"sync_on_upload"`
+}
+
+// Bucket implements objstore.Bucket backed by a local directory. Uploads are
+// written to a temp file and atomically renamed into place so that a reader
+// never observes a partially written object.
+type Bucket struct {
+	rootDir      string
+	syncOnUpload bool
+}
+
+func NewBucket(cfg Config) (*Bucket, error) {
+	if cfg.Directory == "" {
+		return nil, errors.New("filesystem bucket: directory is required")
+	}
+	absDir, err := filepath.Abs(cfg.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("resolving filesystem bucket directory: %w", err)
+	}
+	if err := os.MkdirAll(absDir, 0750); err != nil {
+		return nil, fmt.Errorf("creating filesystem bucket directory: %w", err)
+	}
+	return &Bucket{rootDir: absDir, syncOnUpload: cfg.SyncOnUpload}, nil
+}
+
+func (b *Bucket) Name() string { return "filesystem: " + b.rootDir }
+
+func (b *Bucket) fullPath(name string) string {
+	return filepath.Join(b.rootDir, filepath.FromSlash(name))
+}
+
+// Upload writes r to name via a temp file in the same directory followed by
+// an atomic rename, so concurrent readers never see a half-written object.
+// ENOSPC is surfaced as a non-retriable error: retrying an upload will not
+// free disk space.
+func (b *Bucket) Upload(ctx context.Context, name string, r io.Reader) error {
+	dst := b.fullPath(name)
+	if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+		return fmt.Errorf("creating parent directories for %s: %w", name, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".tmp-*")
+	if err != nil {
+		return wrapWriteErr(name, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once renamed
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return wrapWriteErr(name, err)
+	}
+	if b.syncOnUpload {
+		if err := tmp.Sync(); err != nil {
+			tmp.Close()
+			return wrapWriteErr(name, err)
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return wrapWriteErr(name, err)
+	}
+	if err := os.Rename(tmpPath, dst); err != nil {
+		return wrapWriteErr(name, err)
+	}
+	if b.syncOnUpload {
+		if err := syncDir(filepath.Dir(dst)); err != nil {
+			return wrapWriteErr(name, err)
+		}
+	}
+	return nil
+}
+
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Sync()
+}
+
+// nonRetriableErr marks errors that retrying will never fix, such as running
+// out of disk space.
+type nonRetriableErr struct{ err error }
+
+func (e *nonRetriableErr) Error() string    { return e.err.Error() }
+func (e *nonRetriableErr) Unwrap() error    { return e.err }
+func (e *nonRetriableErr) Retriable() bool  { return false }
+
+// IsNonRetriable reports whether err should not be retried, e.g. ENOSPC.
+func IsNonRetriable(err error) bool {
+	var nre *nonRetriableErr
+	return errors.As(err, &nre)
+}
+
+func wrapWriteErr(name string, err error) error {
+	if errors.Is(err, syscall.ENOSPC) {
+		return &nonRetriableErr{fmt.Errorf("no space left on device writing %s: %w", name, err)}
+	}
+	return fmt.Errorf("writing %s: %w", name, err)
+}
+
+func (b *Bucket) Delete(ctx context.Context, name string) error {
+	if err := os.Remove(b.fullPath(name)); err != nil {
+		return fmt.Errorf("deleting %s: %w", name, err)
+	}
+	return nil
+}
+
+func (b *Bucket) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	f, err := os.Open(b.fullPath(name))
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (b *Bucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	f, err := os.Open(b.fullPath(name))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(off, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(f, length), f}, nil
+}
+
+func (b *Bucket) Exists(ctx context.Context, name string) (bool, error) {
+	_, err := os.Stat(b.fullPath(name))
+	if err != nil {
+		if b.IsObjNotFoundErr(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (b *Bucket) IsObjNotFoundErr(err error) bool {
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// Iter calls f for each entry under dir, paginating the directory read so
+// that prefixes with very large fan-out don't require buffering the full
+// listing in memory at once.
+func (b *Bucket) Iter(ctx context.Context, dir string, f func(name string) error) error {
+	return b.iter(ctx, dir, func(name string, _ objstore.ObjectAttributes) error {
+		return f(name)
+	})
+}
+
+// IterWithAttributes additionally reports each entry's size and
+// modification time, both already available from the directory read, so no
+// extra os.Stat call is needed.
+func (b *Bucket) IterWithAttributes(ctx context.Context, dir string, f func(name string, attrs objstore.ObjectAttributes) error) error {
+	return b.iter(ctx, dir, f)
+}
+
+func (b *Bucket) iter(ctx context.Context, dir string, f func(name string, attrs objstore.ObjectAttributes) error) error {
+	const pageSize = 1024
+
+	full := b.fullPath(dir)
+	d, err := os.Open(full)
+	if err != nil {
+		if b.IsObjNotFoundErr(err) {
+			return nil
+		}
+		return fmt.Errorf("opening %s: %w", dir, err)
+	}
+	defer d.Close()
+
+	prefix := strings.TrimSuffix(dir, "/")
+	for {
+		entries, err := d.ReadDir(pageSize)
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("listing %s: %w", dir, err)
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if prefix != "" {
+				name = prefix + "/" + name
+			}
+			var attrs objstore.ObjectAttributes
+			if info, err := e.Info(); err == nil {
+				attrs.Size = info.Size()
+				attrs.LastModified = info.ModTime()
+			}
+			if e.IsDir() {
+				name += "/"
+			}
+			if err := f(name, attrs); err != nil {
+				return err
+			}
+		}
+		if len(entries) < pageSize {
+			return nil
+		}
+	}
+}
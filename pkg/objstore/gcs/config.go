@@ -0,0 +1,71 @@
+package gcs
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/storage"
+	gax "github.com/googleapis/gax-go/v2"
+
+	"github.com/thanos-io/thanos/pkg/objstore"
+)
+
+// Config configures the GCS bucket client.
+type Config struct {
+	Bucket         string `yaml:"bucket"`
+	ServiceAccount string `yaml:"service_account"`
+
+	// KMSKeyName, when set, is applied to every object write as its
+	// customer-managed encryption key (CMEK), e.g.
+	// "projects/P/locations/L/keyRings/R/cryptoKeys/K".
+	KMSKeyName string `yaml:"kms_key_name"`
+
+	RetryConfig RetryConfig `yaml:"retry"`
+
+	// ChunkRetry tunes the resumable (chunked) upload used for objects
+	// larger than one chunk, i.e. compactor block segment files.
+	ChunkRetry objstore.MultipartConfig `yaml:"chunking"`
+}
+
+// ApplyChunking sets w's ChunkSize from cfg.ChunkRetry, so large writes are
+// sent as a sequence of resumable chunks rather than buffered whole in
+// memory.
+func (c Config) ApplyChunking(w *storage.Writer) {
+	if c.ChunkRetry.PartSize > 0 {
+		w.ChunkSize = int(c.ChunkRetry.PartSize)
+	}
+}
+
+// RetryConfig exposes the knobs of the GCS client library's exponential
+// backoff, so a bucket under heavy load can be tuned without recompiling.
+type RetryConfig struct {
+	MaxAttempts int           `yaml:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+	BackoffMultiplier float64    `yaml:"backoff_multiplier"`
+}
+
+// NewWriter returns a storage.Writer for o with cfg's CMEK key applied, if
+// configured.
+func (c Config) NewWriter(ctx context.Context, o *storage.ObjectHandle) *storage.Writer {
+	w := o.NewWriter(ctx)
+	w.KMSKeyName = c.KMSKeyName
+	return w
+}
+
+// RetryOptions translates RetryConfig into GCS client retry options.
+func (r RetryConfig) RetryOptions() []storage.RetryOption {
+	opts := []storage.RetryOption{}
+	if r.MaxAttempts > 0 {
+		opts = append(opts, storage.WithMaxAttempts(r.MaxAttempts))
+	}
+	if r.InitialBackoff > 0 || r.MaxBackoff > 0 || r.BackoffMultiplier > 0 {
+		backoff := gax.Backoff{
+			Initial:    r.InitialBackoff,
+			Max:        r.MaxBackoff,
+			Multiplier: r.BackoffMultiplier,
+		}
+		opts = append(opts, storage.WithBackoff(backoff))
+	}
+	return opts
+}
@@ -0,0 +1,111 @@
+package objstore
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// HedgeConfig configures request hedging for GetRange.
+type HedgeConfig struct {
+	// Delay is how long to wait for the first GetRange to complete before
+	// firing a duplicate request and racing the two.
+	Delay time.Duration `yaml:"delay"`
+	// MaxExtraRequestsPerSecond caps how many hedge requests can be fired,
+	// so a provider having a bad day doesn't get doubled traffic on top of
+	// its existing load.
+	MaxExtraRequestsPerSecond float64 `yaml:"max_extra_requests_per_second"`
+}
+
+// HedgedBucket wraps a Bucket and hedges GetRange: if the first request
+// hasn't returned within cfg.Delay, a second, identical request is sent and
+// whichever responds first wins, cutting tail latency on object stores that
+// occasionally have a slow individual request without being slow overall.
+type HedgedBucket struct {
+	Bucket
+	delay   time.Duration
+	limiter *rate.Limiter
+}
+
+// NewHedgedBucket wraps b with cfg's hedging policy.
+func NewHedgedBucket(b Bucket, cfg HedgeConfig) *HedgedBucket {
+	limit := rate.Inf
+	if cfg.MaxExtraRequestsPerSecond > 0 {
+		limit = rate.Limit(cfg.MaxExtraRequestsPerSecond)
+	}
+	return &HedgedBucket{
+		Bucket:  b,
+		delay:   cfg.Delay,
+		limiter: rate.NewLimiter(limit, 1),
+	}
+}
+
+type rangeResult struct {
+	rc  io.ReadCloser
+	err error
+}
+
+func (h *HedgedBucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	if h.delay <= 0 {
+		return h.Bucket.GetRange(ctx, name, off, length)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	results := make(chan rangeResult, 2)
+
+	fire := func() {
+		rc, err := h.Bucket.GetRange(ctx, name, off, length)
+		results <- rangeResult{rc, err}
+	}
+
+	go fire()
+
+	timer := time.NewTimer(h.delay)
+	defer timer.Stop()
+
+	var hedged bool
+	for {
+		select {
+		case res := <-results:
+			if res.err != nil {
+				if !hedged && h.limiter.Allow() {
+					// Give the hedge request a chance before giving up on
+					// the whole call.
+					hedged = true
+					go fire()
+					continue
+				}
+				cancel()
+				return nil, res.err
+			}
+			// Do not cancel ctx yet: it is shared with an in-flight hedge
+			// goroutine, and canceling it here would also tear down the
+			// winning stream we are about to hand back to the caller.
+			// Deferred to Close() once the body has been fully consumed.
+			return &cancelingReadCloser{ReadCloser: res.rc, cancel: cancel}, nil
+		case <-timer.C:
+			if !hedged && h.limiter.Allow() {
+				hedged = true
+				go fire()
+			}
+		case <-ctx.Done():
+			cancel()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// cancelingReadCloser cancels the losing hedge request's context once the
+// winning response body has been fully read and closed.
+type cancelingReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelingReadCloser) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,19 @@
+package objstore
+
+// MultipartConfig tunes multi-part/chunked upload behavior shared across
+// providers that support it (S3, GCS resumable uploads, Azure block blobs).
+// Large compactor output blocks are upload-bandwidth bound on a single
+// stream, so these knobs let an operator trade memory and concurrent
+// connections for throughput.
+type MultipartConfig struct {
+	// PartSize is the size, in bytes, of each part/chunk/block uploaded
+	// concurrently. Zero uses the provider SDK's default.
+	PartSize int64 `yaml:"part_size"`
+	// Concurrency bounds how many parts are in flight at once for a single
+	// object upload. Zero uses the provider SDK's default.
+	Concurrency int `yaml:"concurrency"`
+	// DisableChecksum skips per-part checksum computation/validation, when
+	// the provider supports that trade-off, to save CPU on already-trusted
+	// local networks.
+	DisableChecksum bool `yaml:"disable_checksum"`
+}
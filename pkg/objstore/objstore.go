@@ -0,0 +1,52 @@
+// Package objstore provides a common object storage abstraction that every
+// Thanos component uses to read and write blocks, regardless of the backing
+// cloud provider.
+package objstore
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Bucket is a read/write object storage abstraction implemented by every
+// supported provider (S3, GCS, Azure, Swift, filesystem, ...).
+type Bucket interface {
+	BucketReader
+
+	// Upload writes the contents of r to name, overwriting any existing
+	// object.
+	Upload(ctx context.Context, name string, r io.Reader) error
+	// Delete removes name from the bucket.
+	Delete(ctx context.Context, name string) error
+	// Name returns a human-readable identifier for the bucket, used in logs
+	// and metrics.
+	Name() string
+}
+
+// BucketReader supports read-only access to a bucket.
+type BucketReader interface {
+	// Iter calls f for each entry under dir (non-recursively, like ls).
+	Iter(ctx context.Context, dir string, f func(name string) error) error
+	// IterWithAttributes is like Iter, but additionally hands f the size and
+	// last-modified time of each object, when the underlying provider
+	// exposes them during listing. This lets callers like the block
+	// fetcher, cleaner and `bucket inspect` avoid a per-object round trip
+	// just to read attributes that listing already returned.
+	IterWithAttributes(ctx context.Context, dir string, f func(name string, attrs ObjectAttributes) error) error
+	// Get returns a reader for the whole object.
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+	// GetRange returns a reader for the given byte range of the object.
+	GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error)
+	// Exists reports whether the object exists.
+	Exists(ctx context.Context, name string) (bool, error)
+	// IsObjNotFoundErr reports whether err indicates a missing object.
+	IsObjNotFoundErr(err error) bool
+}
+
+// ObjectAttributes holds the metadata a provider can return cheaply during
+// listing, without a separate per-object round trip.
+type ObjectAttributes struct {
+	Size         int64
+	LastModified time.Time
+}
@@ -0,0 +1,70 @@
+package objstore
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+// PrefixedBucket scopes every operation on a wrapped Bucket under a fixed
+// key prefix, so that multiple independent Thanos installations can share
+// a single underlying bucket without namespace collisions. It is the single
+// implementation of prefixing; providers must not implement prefixing
+// themselves.
+type PrefixedBucket struct {
+	Bucket
+	prefix string
+}
+
+// NewPrefixedBucket returns a Bucket that transparently prepends prefix to
+// every object name passed to b. An empty prefix returns b unwrapped.
+func NewPrefixedBucket(b Bucket, prefix string) Bucket {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return b
+	}
+	return &PrefixedBucket{Bucket: b, prefix: prefix}
+}
+
+func (p *PrefixedBucket) withPrefix(name string) string {
+	if name == "" {
+		return p.prefix
+	}
+	return p.prefix + "/" + name
+}
+
+func (p *PrefixedBucket) stripPrefix(name string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(name, p.prefix), "/")
+}
+
+func (p *PrefixedBucket) Upload(ctx context.Context, name string, r io.Reader) error {
+	return p.Bucket.Upload(ctx, p.withPrefix(name), r)
+}
+
+func (p *PrefixedBucket) Delete(ctx context.Context, name string) error {
+	return p.Bucket.Delete(ctx, p.withPrefix(name))
+}
+
+func (p *PrefixedBucket) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	return p.Bucket.Get(ctx, p.withPrefix(name))
+}
+
+func (p *PrefixedBucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	return p.Bucket.GetRange(ctx, p.withPrefix(name), off, length)
+}
+
+func (p *PrefixedBucket) Exists(ctx context.Context, name string) (bool, error) {
+	return p.Bucket.Exists(ctx, p.withPrefix(name))
+}
+
+func (p *PrefixedBucket) Iter(ctx context.Context, dir string, f func(name string) error) error {
+	return p.Bucket.Iter(ctx, p.withPrefix(dir), func(name string) error {
+		return f(p.stripPrefix(name))
+	})
+}
+
+func (p *PrefixedBucket) IterWithAttributes(ctx context.Context, dir string, f func(name string, attrs ObjectAttributes) error) error {
+	return p.Bucket.IterWithAttributes(ctx, p.withPrefix(dir), func(name string, attrs ObjectAttributes) error {
+		return f(p.stripPrefix(name), attrs)
+	})
+}
@@ -0,0 +1,46 @@
+package objstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// ErrReadOnly is returned by a ReadOnlyBucket's mutating methods.
+var ErrReadOnly = fmt.Errorf("bucket is configured read-only")
+
+// ReadOnlyBucket wraps a Bucket and rejects every mutating operation, so a
+// misconfigured or exploratory tool (e.g. testing compactor planning logic
+// against a production bucket) can never write to it. If DryRun is set,
+// mutating calls are logged and treated as a no-op success instead of
+// returning ErrReadOnly.
+type ReadOnlyBucket struct {
+	Bucket
+	logger log.Logger
+	dryRun bool
+}
+
+// NewReadOnlyBucket wraps b so that Upload and Delete either fail with
+// ErrReadOnly, or, if dryRun is true, are logged and skipped.
+func NewReadOnlyBucket(b Bucket, logger log.Logger, dryRun bool) *ReadOnlyBucket {
+	return &ReadOnlyBucket{Bucket: b, logger: logger, dryRun: dryRun}
+}
+
+func (r *ReadOnlyBucket) Upload(ctx context.Context, name string, _ io.Reader) error {
+	if r.dryRun {
+		level.Info(r.logger).Log("msg", "dry-run: skipping upload", "name", name)
+		return nil
+	}
+	return fmt.Errorf("upload %s: %w", name, ErrReadOnly)
+}
+
+func (r *ReadOnlyBucket) Delete(ctx context.Context, name string) error {
+	if r.dryRun {
+		level.Info(r.logger).Log("msg", "dry-run: skipping delete", "name", name)
+		return nil
+	}
+	return fmt.Errorf("delete %s: %w", name, ErrReadOnly)
+}
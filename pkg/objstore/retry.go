@@ -0,0 +1,172 @@
+package objstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RetryConfig configures the provider-agnostic retry layer applied on top
+// of a Bucket, smoothing over the wildly different built-in retry behavior
+// (or lack thereof) across providers.
+type RetryConfig struct {
+	MaxRetries int           `yaml:"max_retries"`
+	MinBackoff time.Duration `yaml:"min_backoff"`
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+	// PerOpTimeout bounds a single attempt, independent of the context
+	// passed in by the caller. Zero disables the per-attempt timeout.
+	PerOpTimeout time.Duration `yaml:"per_operation_timeout"`
+}
+
+// DefaultRetryConfig is a conservative default: a handful of retries with
+// exponential backoff capped well under typical client request timeouts.
+var DefaultRetryConfig = RetryConfig{
+	MaxRetries: 3,
+	MinBackoff: 100 * time.Millisecond,
+	MaxBackoff: 2 * time.Second,
+}
+
+// RetriableErr is satisfied by errors that classify themselves explicitly,
+// such as objstore/filesystem's ENOSPC wrapper, which retrying can never
+// fix.
+type RetriableErr interface {
+	error
+	Retriable() bool
+}
+
+// RetryingBucket wraps a Bucket and retries failed operations according to
+// cfg, skipping retries for errors classified as non-retriable (missing
+// objects, ENOSPC, and anything satisfying RetriableErr with Retriable()
+// false).
+type RetryingBucket struct {
+	Bucket
+	cfg     RetryConfig
+	retries *prometheus.CounterVec
+}
+
+// NewRetryingBucket wraps b with cfg's retry policy.
+func NewRetryingBucket(b Bucket, cfg RetryConfig, reg prometheus.Registerer) *RetryingBucket {
+	return &RetryingBucket{
+		Bucket: b,
+		cfg:    cfg,
+		retries: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "thanos_objstore_bucket_operation_retries_total",
+			Help: "Total number of retried bucket operations, by operation.",
+		}, []string{"operation"}),
+	}
+}
+
+func (r *RetryingBucket) isRetriable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if r.Bucket.IsObjNotFoundErr(err) {
+		return false
+	}
+	var re RetriableErr
+	if errors.As(err, &re) {
+		return re.Retriable()
+	}
+	return true
+}
+
+func (r *RetryingBucket) backoff(attempt int) time.Duration {
+	d := r.cfg.MinBackoff * time.Duration(math.Pow(2, float64(attempt)))
+	if r.cfg.MaxBackoff > 0 && d > r.cfg.MaxBackoff {
+		return r.cfg.MaxBackoff
+	}
+	return d
+}
+
+func (r *RetryingBucket) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.cfg.PerOpTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.cfg.PerOpTimeout)
+}
+
+func (r *RetryingBucket) do(ctx context.Context, op string, f func(ctx context.Context) error) error {
+	var lastErr error
+	for attempt := 0; attempt <= r.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			r.retries.WithLabelValues(op).Inc()
+			select {
+			case <-time.After(r.backoff(attempt - 1)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		opCtx, cancel := r.withTimeout(ctx)
+		lastErr = f(opCtx)
+		cancel()
+		if lastErr == nil || !r.isRetriable(lastErr) {
+			return lastErr
+		}
+	}
+	return lastErr
+}
+
+func (r *RetryingBucket) Upload(ctx context.Context, name string, rd io.Reader) error {
+	// Upload's reader can only be consumed once, so a mid-stream failure
+	// cannot be safely retried without the caller buffering the payload;
+	// retry only applies to the wrapped call failing before it reads rd.
+	return r.do(ctx, "upload", func(ctx context.Context) error {
+		return r.Bucket.Upload(ctx, name, rd)
+	})
+}
+
+func (r *RetryingBucket) Delete(ctx context.Context, name string) error {
+	return r.do(ctx, "delete", func(ctx context.Context) error {
+		return r.Bucket.Delete(ctx, name)
+	})
+}
+
+func (r *RetryingBucket) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	var rc io.ReadCloser
+	err := r.do(ctx, "get", func(ctx context.Context) error {
+		var err error
+		rc, err = r.Bucket.Get(ctx, name)
+		return err
+	})
+	return rc, err
+}
+
+func (r *RetryingBucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	var rc io.ReadCloser
+	err := r.do(ctx, "get_range", func(ctx context.Context) error {
+		var err error
+		rc, err = r.Bucket.GetRange(ctx, name, off, length)
+		return err
+	})
+	return rc, err
+}
+
+func (r *RetryingBucket) Exists(ctx context.Context, name string) (bool, error) {
+	var exists bool
+	err := r.do(ctx, "exists", func(ctx context.Context) error {
+		var err error
+		exists, err = r.Bucket.Exists(ctx, name)
+		return err
+	})
+	return exists, err
+}
+
+func (r *RetryingBucket) IterWithAttributes(ctx context.Context, dir string, f func(name string, attrs ObjectAttributes) error) error {
+	return r.do(ctx, "iter", func(ctx context.Context) error {
+		return r.Bucket.IterWithAttributes(ctx, dir, f)
+	})
+}
+
+func (r *RetryingBucket) Iter(ctx context.Context, dir string, f func(name string) error) error {
+	return r.do(ctx, "iter", func(ctx context.Context) error {
+		return r.Bucket.Iter(ctx, dir, f)
+	})
+}
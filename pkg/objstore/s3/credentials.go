@@ -0,0 +1,41 @@
+package s3
+
+import (
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// CredentialsConfig picks how the S3 client authenticates, beyond plain
+// static access/secret keys.
+type CredentialsConfig struct {
+	// WebIdentityTokenFile and RoleARN enable IRSA: EKS projects a Kubernetes
+	// service account token at this path, which is exchanged for temporary
+	// credentials scoped to RoleARN via sts:AssumeRoleWithWebIdentity.
+	WebIdentityTokenFile string `yaml:"web_identity_token_file"`
+	RoleARN              string `yaml:"role_arn"`
+	RoleSessionName      string `yaml:"role_session_name"`
+
+	// AssumeRoleARN, set without WebIdentityTokenFile, does a plain
+	// sts:AssumeRole using the session's existing credentials (e.g. an
+	// instance profile) to obtain a differently-scoped role.
+	AssumeRoleARN string `yaml:"assume_role_arn"`
+}
+
+// NewCredentials builds an *credentials.Credentials provider chain for cfg,
+// falling back to the SDK's default provider chain (env vars, shared config,
+// instance metadata) when neither IRSA nor AssumeRole is configured.
+func NewCredentials(sess *session.Session, cfg CredentialsConfig) (*credentials.Credentials, error) {
+	switch {
+	case cfg.WebIdentityTokenFile != "" && cfg.RoleARN != "":
+		sessionName := cfg.RoleSessionName
+		if sessionName == "" {
+			sessionName = "thanos"
+		}
+		return stscreds.NewWebIdentityCredentials(sess, cfg.RoleARN, sessionName, cfg.WebIdentityTokenFile), nil
+	case cfg.AssumeRoleARN != "":
+		return stscreds.NewCredentials(sess, cfg.AssumeRoleARN), nil
+	default:
+		return sess.Config.Credentials, nil
+	}
+}
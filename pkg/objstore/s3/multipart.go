@@ -0,0 +1,18 @@
+package s3
+
+import (
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	"github.com/thanos-io/thanos/pkg/objstore"
+)
+
+// ApplyMultipartConfig tunes u in place from cfg, for throughput on large
+// compactor block uploads that are otherwise bound to a single stream.
+func ApplyMultipartConfig(u *s3manager.Uploader, cfg objstore.MultipartConfig) {
+	if cfg.PartSize > 0 {
+		u.PartSize = cfg.PartSize
+	}
+	if cfg.Concurrency > 0 {
+		u.Concurrency = cfg.Concurrency
+	}
+}
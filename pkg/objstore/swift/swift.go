@@ -0,0 +1,180 @@
+// Package swift implements the objstore.Bucket interface against an
+// OpenStack Swift container.
+package swift
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ncw/swift/v2"
+	"gopkg.in/yaml.v2"
+
+	"github.com/thanos-io/thanos/pkg/objstore"
+)
+
+// Config configures the Swift bucket client. Authentication supports either
+// classic user/password or Keystone v3 application credentials, for
+// deployments where user/password auth is disabled.
+type Config struct {
+	AuthURL   string `yaml:"auth_url"`
+	Container string `yaml:"container"`
+
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	Domain   string `yaml:"domain_name"`
+	Project  string `yaml:"project_name"`
+
+	// ApplicationCredentialID/Secret authenticate via Keystone v3
+	// application credentials instead of username/password.
+	ApplicationCredentialID     string `yaml:"application_credential_id"`
+	ApplicationCredentialName   string `yaml:"application_credential_name"`
+	ApplicationCredentialSecret string `yaml:"application_credential_secret"`
+
+	// LargeObjectSegmentSize is the size, in bytes, of the segments of the
+	// static large object that every Upload writes. Zero uses a
+	// conservative default.
+	LargeObjectSegmentSize int64 `yaml:"large_object_segment_size"`
+
+	// AutoCreateContainer creates Container on startup if it does not
+	// already exist.
+	AutoCreateContainer bool `yaml:"auto_create_container"`
+}
+
+const defaultSegmentSize = 1 << 30 // 1GiB
+
+func ParseConfig(content []byte) (Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(content, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parsing Swift config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Bucket implements objstore.Bucket for an OpenStack Swift container.
+type Bucket struct {
+	name        string
+	conn        *swift.Connection
+	segmentSize int64
+}
+
+func NewBucket(ctx context.Context, cfg Config) (*Bucket, error) {
+	conn := &swift.Connection{
+		AuthUrl:     cfg.AuthURL,
+		UserName:    cfg.Username,
+		ApiKey:      cfg.Password,
+		Domain:      cfg.Domain,
+		Tenant:      cfg.Project,
+		AuthVersion: 3,
+	}
+	if cfg.ApplicationCredentialID != "" || cfg.ApplicationCredentialName != "" {
+		conn.ApplicationCredentialId = cfg.ApplicationCredentialID
+		conn.ApplicationCredentialName = cfg.ApplicationCredentialName
+		conn.ApplicationCredentialSecret = cfg.ApplicationCredentialSecret
+		// Application credentials carry their own scope; plain user/password
+		// fields must be left empty or Keystone rejects the auth request.
+		conn.UserName = ""
+		conn.ApiKey = ""
+	}
+
+	if err := conn.Authenticate(ctx); err != nil {
+		return nil, fmt.Errorf("authenticating to Swift: %w", err)
+	}
+
+	segmentSize := cfg.LargeObjectSegmentSize
+	if segmentSize <= 0 {
+		segmentSize = defaultSegmentSize
+	}
+
+	if cfg.AutoCreateContainer {
+		if err := conn.ContainerCreate(ctx, cfg.Container, nil); err != nil {
+			return nil, fmt.Errorf("creating Swift container %s: %w", cfg.Container, err)
+		}
+	}
+
+	return &Bucket{name: cfg.Container, conn: conn, segmentSize: segmentSize}, nil
+}
+
+func (b *Bucket) Name() string { return b.name }
+
+// Upload writes r as a static large object segmented at b.segmentSize, so
+// that objects larger than Swift's single-part limit (typically 5GiB)
+// upload correctly regardless of size.
+func (b *Bucket) Upload(ctx context.Context, name string, r io.Reader) error {
+	w, err := b.conn.StaticLargeObjectCreateFile(ctx, &swift.LargeObjectOpts{
+		Container:  b.name,
+		ObjectName: name,
+		ChunkSize:  b.segmentSize,
+	})
+	if err != nil {
+		return fmt.Errorf("creating Swift large object writer for %s: %w", name, err)
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return fmt.Errorf("uploading %s: %w", name, err)
+	}
+	return w.Close()
+}
+
+func (b *Bucket) Delete(ctx context.Context, name string) error {
+	return b.conn.ObjectDelete(ctx, b.name, name)
+}
+
+func (b *Bucket) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	f, _, err := b.conn.ObjectOpen(ctx, b.name, name, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (b *Bucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	headers := swift.Headers{"Range": fmt.Sprintf("bytes=%d-%d", off, off+length-1)}
+	f, _, err := b.conn.ObjectOpen(ctx, b.name, name, false, headers)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (b *Bucket) Exists(ctx context.Context, name string) (bool, error) {
+	_, _, err := b.conn.Object(ctx, b.name, name)
+	if err != nil {
+		if b.IsObjNotFoundErr(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (b *Bucket) IsObjNotFoundErr(err error) bool {
+	return err == swift.ObjectNotFound || err == swift.ContainerNotFound
+}
+
+func (b *Bucket) Iter(ctx context.Context, dir string, f func(name string) error) error {
+	return b.IterWithAttributes(ctx, dir, func(name string, _ objstore.ObjectAttributes) error {
+		return f(name)
+	})
+}
+
+func (b *Bucket) IterWithAttributes(ctx context.Context, dir string, f func(name string, attrs objstore.ObjectAttributes) error) error {
+	opts := &swift.ObjectsOpts{Prefix: dir, Delimiter: '/'}
+	return b.conn.ObjectsWalk(ctx, b.name, opts, func(ctx context.Context, opts *swift.ObjectsOpts) (interface{}, error) {
+		objs, err := b.conn.Objects(ctx, b.name, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing Swift objects under %s: %w", dir, err)
+		}
+		for _, o := range objs {
+			name := o.Name
+			if name == "" {
+				name = o.SubDir
+			}
+			attrs := objstore.ObjectAttributes{Size: o.Bytes, LastModified: o.LastModified}
+			if err := f(name, attrs); err != nil {
+				return nil, err
+			}
+		}
+		return objs, nil
+	})
+}
@@ -0,0 +1,42 @@
+package objstore
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/time/rate"
+)
+
+// ThrottledBucket wraps a Bucket and rate-limits the bytes passed to Upload,
+// so a sidecar doing an initial backfill of historical blocks doesn't
+// saturate the node's egress bandwidth and starve other traffic.
+type ThrottledBucket struct {
+	Bucket
+	limiter *rate.Limiter
+}
+
+// NewThrottledBucket limits uploads to bytesPerSecond, bursting up to one
+// second's worth of traffic.
+func NewThrottledBucket(b Bucket, bytesPerSecond int) *ThrottledBucket {
+	return &ThrottledBucket{Bucket: b, limiter: rate.NewLimiter(rate.Limit(bytesPerSecond), bytesPerSecond)}
+}
+
+func (t *ThrottledBucket) Upload(ctx context.Context, name string, r io.Reader) error {
+	return t.Bucket.Upload(ctx, name, &throttledReader{ctx: ctx, r: r, limiter: t.limiter})
+}
+
+type throttledReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *rate.Limiter
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	if n > 0 {
+		if werr := t.limiter.WaitN(t.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,127 @@
+package objstore
+
+import (
+	"context"
+	"io"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// sizeClass buckets an object size into a small label cardinality so that
+// per-size-class histograms don't explode into one series per byte count.
+func sizeClass(n int64) string {
+	switch {
+	case n < 0:
+		return "unknown"
+	case n < 1<<10:
+		return "<1KiB"
+	case n < 1<<20:
+		return "<1MiB"
+	case n < 16<<20:
+		return "<16MiB"
+	case n < 128<<20:
+		return "<128MiB"
+	default:
+		return ">=128MiB"
+	}
+}
+
+// TracingBucket wraps a Bucket, emitting an OpenTracing span and a
+// duration histogram (labelled by operation and size class) for every
+// call, so that slow queries can be attributed to e.g. GetRange tail
+// latency on a specific provider.
+type TracingBucket struct {
+	Bucket
+
+	duration *prometheus.HistogramVec
+}
+
+// NewTracingBucket wraps b with tracing spans and latency histograms.
+// buckets configures the histogram bucket boundaries in seconds; a nil
+// slice uses prometheus.DefBuckets.
+func NewTracingBucket(b Bucket, reg prometheus.Registerer, buckets []float64) *TracingBucket {
+	if buckets == nil {
+		buckets = prometheus.DefBuckets
+	}
+	return &TracingBucket{
+		Bucket: b,
+		duration: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "thanos_objstore_bucket_operation_duration_seconds",
+			Help:    "Duration of bucket operations, by operation and object size class.",
+			Buckets: buckets,
+		}, []string{"operation", "size_class"}),
+	}
+}
+
+func (t *TracingBucket) trace(ctx context.Context, op string, size int64, f func(ctx context.Context) error) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "objstore."+op)
+	defer span.Finish()
+
+	start := time.Now()
+	err := f(ctx)
+	t.duration.WithLabelValues(op, sizeClass(size)).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogKV("error", err.Error())
+	}
+	return err
+}
+
+func (t *TracingBucket) Upload(ctx context.Context, name string, r io.Reader) error {
+	return t.trace(ctx, "upload", -1, func(ctx context.Context) error {
+		return t.Bucket.Upload(ctx, name, r)
+	})
+}
+
+func (t *TracingBucket) Delete(ctx context.Context, name string) error {
+	return t.trace(ctx, "delete", -1, func(ctx context.Context) error {
+		return t.Bucket.Delete(ctx, name)
+	})
+}
+
+func (t *TracingBucket) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	var rc io.ReadCloser
+	err := t.trace(ctx, "get", -1, func(ctx context.Context) error {
+		var err error
+		rc, err = t.Bucket.Get(ctx, name)
+		return err
+	})
+	return rc, err
+}
+
+func (t *TracingBucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	var rc io.ReadCloser
+	err := t.trace(ctx, "get_range", length, func(ctx context.Context) error {
+		var err error
+		rc, err = t.Bucket.GetRange(ctx, name, off, length)
+		return err
+	})
+	return rc, err
+}
+
+func (t *TracingBucket) Exists(ctx context.Context, name string) (bool, error) {
+	var exists bool
+	err := t.trace(ctx, "exists", -1, func(ctx context.Context) error {
+		var err error
+		exists, err = t.Bucket.Exists(ctx, name)
+		return err
+	})
+	return exists, err
+}
+
+func (t *TracingBucket) IterWithAttributes(ctx context.Context, dir string, f func(name string, attrs ObjectAttributes) error) error {
+	return t.trace(ctx, "iter", -1, func(ctx context.Context) error {
+		return t.Bucket.IterWithAttributes(ctx, dir, f)
+	})
+}
+
+func (t *TracingBucket) Iter(ctx context.Context, dir string, f func(name string) error) error {
+	return t.trace(ctx, "iter", -1, func(ctx context.Context) error {
+		return t.Bucket.Iter(ctx, dir, f)
+	})
+}
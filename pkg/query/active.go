@@ -0,0 +1,92 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ActiveQuery describes a query currently executing in the querier.
+type ActiveQuery struct {
+	ID        uint64
+	Query     string
+	StartTime time.Time
+	cancel    context.CancelFunc
+}
+
+// ActiveQueryTracker records in-flight queries so operators can inspect and
+// cancel a runaway one without restarting the process.
+type ActiveQueryTracker struct {
+	mtx     sync.Mutex
+	nextID  uint64
+	queries map[uint64]*ActiveQuery
+}
+
+func NewActiveQueryTracker() *ActiveQueryTracker {
+	return &ActiveQueryTracker{queries: map[uint64]*ActiveQuery{}}
+}
+
+// Insert registers a starting query and returns a context that is canceled
+// either by the caller's own cancellation or by Cancel(id).
+func (t *ActiveQueryTracker) Insert(ctx context.Context, query string) (context.Context, uint64) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	t.mtx.Lock()
+	t.nextID++
+	id := t.nextID
+	t.queries[id] = &ActiveQuery{ID: id, Query: query, StartTime: time.Now(), cancel: cancel}
+	t.mtx.Unlock()
+
+	return ctx, id
+}
+
+// Delete removes a finished query from the active set.
+func (t *ActiveQueryTracker) Delete(id uint64) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	delete(t.queries, id)
+}
+
+// Cancel stops the query with the given id, if still running.
+func (t *ActiveQueryTracker) Cancel(id uint64) bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	q, ok := t.queries[id]
+	if !ok {
+		return false
+	}
+	q.cancel()
+	delete(t.queries, id)
+	return true
+}
+
+// List returns a snapshot of all currently active queries.
+func (t *ActiveQueryTracker) List() []ActiveQuery {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	out := make([]ActiveQuery, 0, len(t.queries))
+	for _, q := range t.queries {
+		out = append(out, *q)
+	}
+	return out
+}
+
+// ServeHTTP implements GET /api/v1/query/active (list) and
+// DELETE /api/v1/query/active/{id} (cancel).
+func (t *ActiveQueryTracker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodDelete {
+		var id uint64
+		fmt.Sscanf(r.URL.Path, "/api/v1/query/active/%d", &id)
+		if t.Cancel(id) {
+			w.WriteHeader(http.StatusNoContent)
+		} else {
+			http.NotFound(w, r)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(t.List())
+}
@@ -0,0 +1,104 @@
+package query
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Limits bounds the resources a single query is allowed to consume. A zero
+// value field means "no limit".
+//
+// Nothing in this tree calls LimitsFromRequest or SeriesCounter yet: both
+// exist to bound a PromQL query's execution, which requires a
+// query-serving HTTP handler backed by a PromQL engine. `thanos query`
+// here only dials stores and serves /api/v1/stores (see
+// cmd/thanos/query.go's runQuery); it never receives or executes a query
+// request to apply a Limits to. Unlike pkg/dedup or the aggregation
+// pushdown in pkg/store, which take plain series/sample values and so got
+// real offline callers under pkg/analyze (see analyze.DedupBlocks,
+// analyze.Aggregate), LimitsFromRequest is inherently tied to a live
+// *http.Request and SeriesCounter to a live query's in-flight series count
+// - neither of which an offline block-reading tool can manufacture
+// honestly. Wire these in once a real query-execution handler exists;
+// don't fabricate one here just to give this file a caller.
+type Limits struct {
+	MaxSamples int
+	MaxSeries  int
+	Timeout    time.Duration
+}
+
+// LimitsFromRequest overrides the default Limits with any per-query values
+// supplied as query/form parameters, letting a caller tighten (but not
+// loosen) limits on a per-request basis.
+func LimitsFromRequest(r *http.Request, def Limits) (Limits, error) {
+	out := def
+	if v := r.FormValue("max_samples"); v != "" {
+		n, err := parsePositiveInt(v)
+		if err != nil {
+			return out, fmt.Errorf("invalid max_samples: %w", err)
+		}
+		if out.MaxSamples == 0 || n < out.MaxSamples {
+			out.MaxSamples = n
+		}
+	}
+	if v := r.FormValue("max_series"); v != "" {
+		n, err := parsePositiveInt(v)
+		if err != nil {
+			return out, fmt.Errorf("invalid max_series: %w", err)
+		}
+		if out.MaxSeries == 0 || n < out.MaxSeries {
+			out.MaxSeries = n
+		}
+	}
+	if v := r.FormValue("timeout"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return out, fmt.Errorf("invalid timeout: %w", err)
+		}
+		if out.Timeout == 0 || d < out.Timeout {
+			out.Timeout = d
+		}
+	}
+	return out, nil
+}
+
+func parsePositiveInt(v string) (int, error) {
+	var n int
+	if _, err := fmt.Sscanf(v, "%d", &n); err != nil {
+		return 0, err
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("must be positive, got %d", n)
+	}
+	return n, nil
+}
+
+// SeriesCounter tracks the number of series and samples touched by a query
+// and returns an error once a configured Limits is exceeded, so the engine
+// can abort early instead of exhausting memory on a runaway query.
+type SeriesCounter struct {
+	limits  Limits
+	series  int
+	samples int
+}
+
+func NewSeriesCounter(limits Limits) *SeriesCounter {
+	return &SeriesCounter{limits: limits}
+}
+
+func (c *SeriesCounter) AddSeries(n int) error {
+	c.series += n
+	if c.limits.MaxSeries > 0 && c.series > c.limits.MaxSeries {
+		return fmt.Errorf("query exceeded max series limit of %d", c.limits.MaxSeries)
+	}
+	return nil
+}
+
+func (c *SeriesCounter) AddSamples(n int) error {
+	c.samples += n
+	if c.limits.MaxSamples > 0 && c.samples > c.limits.MaxSamples {
+		return fmt.Errorf("query exceeded max samples limit of %d", c.limits.MaxSamples)
+	}
+	return nil
+}
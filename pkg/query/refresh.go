@@ -0,0 +1,62 @@
+package query
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RefreshConfig controls how often the querier re-checks store endpoint
+// health and re-fetches their metadata (external labels, min/max time).
+// A random jitter of up to JitterFraction of each interval is applied per
+// tick so that, with many querier replicas, health checks and metadata
+// refreshes against a shared store fleet don't all land at once.
+type RefreshConfig struct {
+	HealthCheckInterval time.Duration
+	MetadataInterval    time.Duration
+	JitterFraction      float64
+}
+
+// DefaultRefreshConfig matches Thanos' historical fixed defaults.
+var DefaultRefreshConfig = RefreshConfig{
+	HealthCheckInterval: 5 * time.Second,
+	MetadataInterval:    5 * time.Minute,
+	JitterFraction:      0.2,
+}
+
+func jittered(d time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 {
+		return d
+	}
+	delta := time.Duration(float64(d) * fraction * (rand.Float64()*2 - 1))
+	return d + delta
+}
+
+// RunHealthChecks calls check on every tick, jittered around cfg's
+// HealthCheckInterval, until ctx is done.
+func RunHealthChecks(ctx context.Context, cfg RefreshConfig, check func(context.Context)) {
+	runJittered(ctx, cfg.HealthCheckInterval, cfg.JitterFraction, check)
+}
+
+// RunMetadataRefresh calls refresh on every tick, jittered around cfg's
+// MetadataInterval, until ctx is done.
+func RunMetadataRefresh(ctx context.Context, cfg RefreshConfig, refresh func(context.Context)) {
+	runJittered(ctx, cfg.MetadataInterval, cfg.JitterFraction, refresh)
+}
+
+func runJittered(ctx context.Context, interval time.Duration, jitter float64, fn func(context.Context)) {
+	if interval <= 0 {
+		return
+	}
+	timer := time.NewTimer(jittered(interval, jitter))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			fn(ctx)
+			timer.Reset(jittered(interval, jitter))
+		}
+	}
+}
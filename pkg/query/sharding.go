@@ -0,0 +1,41 @@
+package query
+
+import (
+	"hash/fnv"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+// ShardLabelHash hashes lset the same way on the querier (to decide how many
+// vertical shards to plan) and on the store (to filter Series results via
+// storepb.ShardInfo.Owns), so the two sides agree on ownership.
+func ShardLabelHash(lset labels.Labels, by []string) uint64 {
+	h := fnv.New64a()
+	if len(by) == 0 {
+		lset.Range(func(l labels.Label) {
+			h.Write([]byte(l.Name))
+			h.Write([]byte(l.Value))
+		})
+		return h.Sum64()
+	}
+	for _, name := range by {
+		h.Write([]byte(name))
+		h.Write([]byte(lset.Get(name)))
+	}
+	return h.Sum64()
+}
+
+// PlanShards splits a query into n ShardInfos covering disjoint, exhaustive
+// slices of the series space.
+func PlanShards(n uint64, by []string) []*storepb.ShardInfo {
+	if n <= 1 {
+		return []*storepb.ShardInfo{nil}
+	}
+	shards := make([]*storepb.ShardInfo, n)
+	for i := uint64(0); i < n; i++ {
+		shards[i] = &storepb.ShardInfo{ShardIndex: i, TotalShards: n, By: by}
+	}
+	return shards
+}
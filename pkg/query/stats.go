@@ -0,0 +1,60 @@
+package query
+
+import (
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// Stats carries per-query execution statistics returned alongside API
+// responses (under the "stats" JSON field, as Prometheus does) and logged
+// for slow queries.
+//
+// Nothing in this tree populates a Stats or calls SlowQueryLogger.Log yet:
+// both describe the outcome of actually executing a PromQL query, which
+// requires the query-serving handler and engine this tree doesn't carry
+// (see query.Limits' doc comment for the matching gap on the request
+// side). Wire these in alongside that handler; don't fabricate query
+// execution here just to give this file a caller.
+type Stats struct {
+	Query          string        `json:"-"`
+	SamplesTotal   int64         `json:"samplesTotal"`
+	SeriesTotal    int64         `json:"seriesTotal"`
+	PeakSamples    int64         `json:"peakSamples"`
+	ExecutionTime  time.Duration `json:"-"`
+}
+
+// AsJSON mirrors Prometheus' stats response shape (timings in seconds).
+func (s Stats) AsJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"samplesTotal":  s.SamplesTotal,
+		"seriesTotal":   s.SeriesTotal,
+		"peakSamples":   s.PeakSamples,
+		"executionTime": s.ExecutionTime.Seconds(),
+	}
+}
+
+// SlowQueryLogger logs queries whose execution time exceeds threshold.
+type SlowQueryLogger struct {
+	logger    log.Logger
+	threshold time.Duration
+}
+
+func NewSlowQueryLogger(logger log.Logger, threshold time.Duration) *SlowQueryLogger {
+	return &SlowQueryLogger{logger: logger, threshold: threshold}
+}
+
+// Log emits a warning line for s if it exceeded the configured threshold.
+func (l *SlowQueryLogger) Log(s Stats) {
+	if l.threshold <= 0 || s.ExecutionTime < l.threshold {
+		return
+	}
+	level.Warn(l.logger).Log(
+		"msg", "slow query",
+		"query", s.Query,
+		"duration", s.ExecutionTime,
+		"samplesTotal", s.SamplesTotal,
+		"seriesTotal", s.SeriesTotal,
+	)
+}
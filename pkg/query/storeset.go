@@ -0,0 +1,237 @@
+// Package query manages the set of StoreAPI endpoints a querier fans
+// queries out to.
+package query
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+// healthHistoryLen bounds how many HealthPoints StoreSet keeps per
+// endpoint, enough for the querier web UI's health sparkline without
+// growing unbounded on a long-running process.
+const healthHistoryLen = 20
+
+// HealthPoint is one point of a store endpoint's recent health history.
+type HealthPoint struct {
+	Time    time.Time     `json:"time"`
+	Healthy bool          `json:"healthy"`
+	Latency time.Duration `json:"latencyNanos"`
+}
+
+// StoreSpec describes one statically configured store endpoint.
+type StoreSpec struct {
+	Addr   string
+	Strict bool
+}
+
+// StoreSet tracks the live set of store endpoints. Strict stores are always
+// kept in the set, even while unhealthy, and a Series call against them
+// fails loudly instead of being silently dropped, unlike best-effort
+// endpoints discovered via SD or DNS.
+type StoreSet struct {
+	logger log.Logger
+
+	mtx     sync.RWMutex
+	strict  map[string]*storeRef
+	regular map[string]*storeRef
+}
+
+type storeRef struct {
+	spec    StoreSpec
+	healthy bool
+	lastErr error
+
+	// lastInfo, lastLatency and lastChecked hold the most recent Info probe
+	// result; history keeps the last healthHistoryLen probes for the
+	// querier web UI's health sparkline. All are nil/zero until the first
+	// RecordProbe call for this endpoint.
+	lastInfo    *storepb.InfoResponse
+	lastLatency time.Duration
+	lastChecked time.Time
+	history     []HealthPoint
+}
+
+func NewStoreSet(logger log.Logger, specs []StoreSpec) *StoreSet {
+	s := &StoreSet{
+		logger:  logger,
+		strict:  map[string]*storeRef{},
+		regular: map[string]*storeRef{},
+	}
+	for _, spec := range specs {
+		ref := &storeRef{spec: spec, healthy: true}
+		if spec.Strict {
+			s.strict[spec.Addr] = ref
+		} else {
+			s.regular[spec.Addr] = ref
+		}
+	}
+	return s
+}
+
+// MarkUnhealthy records a health check failure for addr. Strict endpoints
+// are kept in the returned Get() set regardless; non-strict endpoints are
+// excluded until they recover.
+func (s *StoreSet) MarkUnhealthy(addr string, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if ref, ok := s.strict[addr]; ok {
+		ref.healthy = false
+		ref.lastErr = err
+		level.Error(s.logger).Log("msg", "strict store endpoint is unhealthy but will not be dropped", "addr", addr, "err", err)
+		return
+	}
+	if ref, ok := s.regular[addr]; ok {
+		ref.healthy = false
+		ref.lastErr = err
+	}
+}
+
+func (s *StoreSet) MarkHealthy(addr string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if ref, ok := s.strict[addr]; ok {
+		ref.healthy = true
+		ref.lastErr = nil
+	}
+	if ref, ok := s.regular[addr]; ok {
+		ref.healthy = true
+		ref.lastErr = nil
+	}
+}
+
+// RecordProbe records the outcome of an Info call against addr: its
+// round-trip latency, the InfoResponse on success (nil on failure), and
+// whether it succeeded. It updates the same healthy/lastErr state
+// MarkHealthy/MarkUnhealthy do, plus the richer per-endpoint detail the
+// querier web UI's stores page renders (advertised time range, label
+// sets, component type) and a bounded health history for its sparkline.
+func (s *StoreSet) RecordProbe(addr string, info *storepb.InfoResponse, latency time.Duration, err error, now time.Time) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	ref, ok := s.strict[addr]
+	if !ok {
+		ref, ok = s.regular[addr]
+	}
+	if !ok {
+		return
+	}
+
+	ref.healthy = err == nil
+	ref.lastErr = err
+	ref.lastLatency = latency
+	ref.lastChecked = now
+	if err == nil {
+		ref.lastInfo = info
+	}
+
+	ref.history = append(ref.history, HealthPoint{Time: now, Healthy: err == nil, Latency: latency})
+	if len(ref.history) > healthHistoryLen {
+		ref.history = ref.history[len(ref.history)-healthHistoryLen:]
+	}
+}
+
+// EndpointStatus is one store endpoint's current health and advertised
+// capabilities, the shape the querier web UI's stores page and
+// /api/v1/stores endpoint render.
+type EndpointStatus struct {
+	Addr          string            `json:"addr"`
+	Strict        bool              `json:"strict"`
+	Healthy       bool              `json:"healthy"`
+	LastError     string            `json:"lastError,omitempty"`
+	LastCheck     time.Time         `json:"lastCheck"`
+	LatencyNanos  int64             `json:"latencyNanos"`
+	ComponentType string            `json:"componentType,omitempty"`
+	StoreType     string            `json:"storeType,omitempty"`
+	MinTime       int64             `json:"minTime"`
+	MaxTime       int64             `json:"maxTime"`
+	LabelSets     [][]storepb.Label `json:"labelSets,omitempty"`
+	History       []HealthPoint     `json:"history,omitempty"`
+}
+
+// Statuses returns every tracked endpoint's EndpointStatus, strict
+// endpoints first, each sorted by address within its group so repeated
+// calls render in a stable order.
+func (s *StoreSet) Statuses() []EndpointStatus {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	out := make([]EndpointStatus, 0, len(s.strict)+len(s.regular))
+	out = append(out, statusesFromRefs(s.strict, true)...)
+	out = append(out, statusesFromRefs(s.regular, false)...)
+	return out
+}
+
+func statusesFromRefs(refs map[string]*storeRef, strict bool) []EndpointStatus {
+	addrs := make([]string, 0, len(refs))
+	for addr := range refs {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	out := make([]EndpointStatus, 0, len(addrs))
+	for _, addr := range addrs {
+		ref := refs[addr]
+		st := EndpointStatus{
+			Addr:         addr,
+			Strict:       strict,
+			Healthy:      ref.healthy,
+			LastCheck:    ref.lastChecked,
+			LatencyNanos: int64(ref.lastLatency),
+			History:      ref.history,
+		}
+		if ref.lastErr != nil {
+			st.LastError = ref.lastErr.Error()
+		}
+		if ref.lastInfo != nil {
+			st.ComponentType = ref.lastInfo.ComponentType
+			st.StoreType = ref.lastInfo.StoreType
+			st.MinTime = ref.lastInfo.MinTime
+			st.MaxTime = ref.lastInfo.MaxTime
+			st.LabelSets = ref.lastInfo.LabelSets
+		}
+		out = append(out, st)
+	}
+	return out
+}
+
+// Get returns the addresses the querier should fan a query out to: every
+// strict endpoint (healthy or not) plus every currently-healthy regular one.
+func (s *StoreSet) Get() ([]string, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	out := make([]string, 0, len(s.strict)+len(s.regular))
+	var errs []error
+	for addr, ref := range s.strict {
+		out = append(out, addr)
+		if !ref.healthy {
+			errs = append(errs, fmt.Errorf("strict store %s is unhealthy: %w", addr, ref.lastErr))
+		}
+	}
+	for addr, ref := range s.regular {
+		if ref.healthy {
+			out = append(out, addr)
+		}
+	}
+	return out, errorsJoin(errs)
+}
+
+func errorsJoin(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	msg := errs[0].Error()
+	for _, e := range errs[1:] {
+		msg += "; " + e.Error()
+	}
+	return fmt.Errorf("%s", msg)
+}
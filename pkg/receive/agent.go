@@ -0,0 +1,36 @@
+package receive
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// Agent runs receive in stateless mode: it accepts remote_write requests and
+// immediately forwards them to a hashring of stateful receivers via
+// QuorumWriter, without appending to a local TSDB. This lets a fleet of
+// lightweight forwarders sit close to write sources (e.g. one per cluster)
+// without each holding a multi-hour WAL of its own.
+type Agent struct {
+	hash    *Ketama
+	replicationFactor int
+	forward func(ctx context.Context, endpoint string, req *prompb.WriteRequest) error
+}
+
+func NewAgent(hash *Ketama, replicationFactor int, forward func(ctx context.Context, endpoint string, req *prompb.WriteRequest) error) *Agent {
+	return &Agent{hash: hash, replicationFactor: replicationFactor, forward: forward}
+}
+
+// Forward routes req to the replicationFactor endpoints owning tenant on the
+// ring and waits for a write quorum among them.
+func (a *Agent) Forward(ctx context.Context, tenant string, req *prompb.WriteRequest) error {
+	endpoints := a.hash.GetN(tenant, a.replicationFactor)
+	if len(endpoints) == 0 {
+		return fmt.Errorf("no endpoints found for tenant %q", tenant)
+	}
+	qw := NewQuorumWriter(a.replicationFactor, func(ctx context.Context, endpoint string) error {
+		return a.forward(ctx, endpoint, req)
+	})
+	return qw.WriteQuorum(ctx, endpoints)
+}
@@ -0,0 +1,57 @@
+package receive
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+// LabelStat counts how many head series carry a given label name or value.
+type LabelStat struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+// CardinalityStats mirrors Prometheus' /api/v1/status/tsdb head stats shape.
+type CardinalityStats struct {
+	SeriesCountByMetricName     []LabelStat `json:"seriesCountByMetricName"`
+	LabelValueCountByLabelName  []LabelStat `json:"labelValueCountByLabelName"`
+	SeriesCountByLabelValuePair []LabelStat `json:"seriesCountByLabelValuePair"`
+}
+
+// HeadStatsProvider is implemented by a tenant's TSDB head.
+type HeadStatsProvider interface {
+	CardinalityStats(limit int) CardinalityStats
+}
+
+// CardinalityAPI exposes per-tenant head cardinality stats at
+// /api/v1/status/tsdb, scoped by the request's tenant, so an operator can
+// find a cardinality-exploding tenant without shelling into the process.
+type CardinalityAPI struct {
+	forTenant func(tenant string) (HeadStatsProvider, error)
+	tenantOf  func(*http.Request) string
+}
+
+func NewCardinalityAPI(forTenant func(tenant string) (HeadStatsProvider, error), tenantOf func(*http.Request) string) *CardinalityAPI {
+	return &CardinalityAPI{forTenant: forTenant, tenantOf: tenantOf}
+}
+
+func (a *CardinalityAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	head, err := a.forTenant(a.tenantOf(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	limit := 10
+	stats := head.CardinalityStats(limit)
+	sortDesc(stats.SeriesCountByMetricName)
+	sortDesc(stats.LabelValueCountByLabelName)
+	sortDesc(stats.SeriesCountByLabelValuePair)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(stats)
+}
+
+func sortDesc(s []LabelStat) {
+	sort.Slice(s, func(i, j int) bool { return s[i].Value > s[j].Value })
+}
@@ -0,0 +1,34 @@
+package receive
+
+import (
+	"github.com/prometheus/prometheus/storage"
+
+	"github.com/thanos-io/thanos/pkg/exemplars"
+)
+
+// ExemplarAppendable is implemented by a tenant's TSDB when it also accepts
+// exemplars (Prometheus' storage.ExemplarAppender), letting receive store
+// exemplars it receives over remote_write without a separate code path per
+// tenant.
+type ExemplarAppendable interface {
+	storage.Appendable
+	storage.ExemplarQueryable
+}
+
+// ExemplarsServer hands out, per tenant, an exemplars.Sidecar (a
+// storepb.ExemplarsServer) that serves that tenant's own TSDB.
+type ExemplarsServer struct {
+	tenantStorage func(tenant string) (storage.ExemplarQueryable, error)
+}
+
+func NewExemplarsServer(tenantStorage func(tenant string) (storage.ExemplarQueryable, error)) *ExemplarsServer {
+	return &ExemplarsServer{tenantStorage: tenantStorage}
+}
+
+func (s *ExemplarsServer) ForTenant(tenant string) (*exemplars.Sidecar, error) {
+	q, err := s.tenantStorage(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return exemplars.NewSidecar(q), nil
+}
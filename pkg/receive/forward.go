@@ -0,0 +1,63 @@
+package receive
+
+import (
+	"sync"
+
+	"google.golang.org/grpc"
+
+	"github.com/thanos-io/thanos/pkg/store"
+)
+
+// ConnPool caches gRPC connections to peer receivers so forwarding a write
+// to another hashring member reuses an existing HTTP/2 connection instead of
+// dialing (and TLS-handshaking) on every request.
+type ConnPool struct {
+	dialOpts []grpc.DialOption
+
+	mtx   sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+// NewConnPool returns a pool that dials peers with comp applied to
+// forwarded write RPCs.
+func NewConnPool(comp store.Compression, extraOpts ...grpc.DialOption) (*ConnPool, error) {
+	opts := append([]grpc.DialOption{}, extraOpts...)
+	compOpt, err := comp.DialOption()
+	if err != nil {
+		return nil, err
+	}
+	if compOpt != nil {
+		opts = append(opts, compOpt)
+	}
+	return &ConnPool{dialOpts: opts, conns: map[string]*grpc.ClientConn{}}, nil
+}
+
+// Get returns a connection to addr, dialing and caching it on first use.
+func (p *ConnPool) Get(addr string) (*grpc.ClientConn, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if c, ok := p.conns[addr]; ok {
+		return c, nil
+	}
+	c, err := grpc.Dial(addr, p.dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	p.conns[addr] = c
+	return c, nil
+}
+
+// Close tears down every pooled connection.
+func (p *ConnPool) Close() error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	var lastErr error
+	for addr, c := range p.conns {
+		if err := c.Close(); err != nil {
+			lastErr = err
+		}
+		delete(p.conns, addr)
+	}
+	return lastErr
+}
@@ -0,0 +1,97 @@
+package receive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HashringConfig describes one hashring: a set of endpoints that share
+// responsibility for a set of tenants.
+type HashringConfig struct {
+	Hash      string   `json:"hash,omitempty"`
+	Tenants   []string `json:"tenants,omitempty"`
+	Endpoints []string `json:"endpoints"`
+}
+
+// HashringConfigWatcher watches a hashring config file on disk and
+// re-parses it whenever it changes, so a hashring rebalance can be rolled
+// out by updating the file without restarting every receiver.
+type HashringConfigWatcher struct {
+	logger   log.Logger
+	path     string
+	interval time.Duration
+	reloads  prometheus.Counter
+	errs     prometheus.Counter
+
+	current []HashringConfig
+	onUpdate func([]HashringConfig)
+}
+
+func NewHashringConfigWatcher(logger log.Logger, reg prometheus.Registerer, path string, interval time.Duration, onUpdate func([]HashringConfig)) *HashringConfigWatcher {
+	w := &HashringConfigWatcher{
+		logger:   logger,
+		path:     path,
+		interval: interval,
+		onUpdate: onUpdate,
+		reloads: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "thanos_receive_hashrings_file_reloads_total",
+			Help: "Number of times the hashring configuration file was reloaded.",
+		}),
+		errs: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "thanos_receive_hashrings_file_reload_errors_total",
+			Help: "Number of failed hashring configuration file reloads.",
+		}),
+	}
+	if reg != nil {
+		reg.MustRegister(w.reloads, w.errs)
+	}
+	return w
+}
+
+// Run polls the config file every interval until ctx is canceled, calling
+// onUpdate with the parsed config whenever its content changes.
+func (w *HashringConfigWatcher) Run(ctx context.Context) {
+	w.reload()
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.reload()
+		}
+	}
+}
+
+func (w *HashringConfigWatcher) reload() {
+	content, err := os.ReadFile(w.path)
+	if err != nil {
+		w.errs.Inc()
+		level.Error(w.logger).Log("msg", "failed to read hashring config file", "path", w.path, "err", err)
+		return
+	}
+	var cfgs []HashringConfig
+	if err := json.Unmarshal(content, &cfgs); err != nil {
+		w.errs.Inc()
+		level.Error(w.logger).Log("msg", "failed to parse hashring config file", "path", w.path, "err", err)
+		return
+	}
+	if reflect.DeepEqual(cfgs, w.current) {
+		return
+	}
+	w.current = cfgs
+	w.reloads.Inc()
+	level.Info(w.logger).Log("msg", "hashring config reloaded", "path", w.path, "hashrings", len(cfgs))
+	w.onUpdate(cfgs)
+}
+
+var errNoSuchHashring = fmt.Errorf("no hashring matches tenant")
@@ -0,0 +1,63 @@
+package receive
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// replicasPerNode is how many virtual nodes each endpoint gets on the
+// ketama ring, smoothing out load distribution across a small hashring.
+const replicasPerNode = 160
+
+// Ketama implements consistent hashing over a hashring's endpoints using the
+// libmemcached ketama algorithm, so adding or removing one endpoint only
+// reshuffles ownership of the keys nearest to it on the ring (roughly 1/N
+// of all keys) instead of most keys, as a naive modulo hashring would.
+type Ketama struct {
+	ring    []uint32
+	byPoint map[uint32]string
+}
+
+// NewKetama builds a ring from endpoints.
+func NewKetama(endpoints []string) *Ketama {
+	k := &Ketama{byPoint: make(map[uint32]string, len(endpoints)*replicasPerNode)}
+	for _, ep := range endpoints {
+		for i := 0; i < replicasPerNode; i++ {
+			h := hashPoint(fmt.Sprintf("%s-%d", ep, i))
+			k.byPoint[h] = ep
+			k.ring = append(k.ring, h)
+		}
+	}
+	sort.Slice(k.ring, func(i, j int) bool { return k.ring[i] < k.ring[j] })
+	return k
+}
+
+// GetN returns the n distinct endpoints responsible for key, walking
+// clockwise around the ring starting at key's hash. n > len(endpoints)
+// returns all endpoints.
+func (k *Ketama) GetN(key string, n int) []string {
+	if len(k.ring) == 0 {
+		return nil
+	}
+	h := hashPoint(key)
+	start := sort.Search(len(k.ring), func(i int) bool { return k.ring[i] >= h })
+
+	seen := map[string]struct{}{}
+	out := make([]string, 0, n)
+	for i := 0; i < len(k.ring) && len(out) < n; i++ {
+		ep := k.byPoint[k.ring[(start+i)%len(k.ring)]]
+		if _, ok := seen[ep]; ok {
+			continue
+		}
+		seen[ep] = struct{}{}
+		out = append(out, ep)
+	}
+	return out
+}
+
+func hashPoint(s string) uint32 {
+	sum := md5.Sum([]byte(s))
+	return binary.LittleEndian.Uint32(sum[0:4])
+}
@@ -0,0 +1,80 @@
+package receive
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// TenantLimits bounds how much a single tenant may ingest. Zero means
+// unlimited.
+type TenantLimits struct {
+	MaxSeries         int `yaml:"max_series"`
+	MaxSamplesPerSend int `yaml:"max_samples_per_send"`
+	// MaxSeriesPerSecond is parsed from the config, but LimitsEnforcer does
+	// not enforce it; only MaxSeries and MaxSamplesPerSend are checked.
+	MaxSeriesPerSecond float64 `yaml:"max_series_per_second"`
+}
+
+// LimitsConfig is the top-level --receive.limits-config document.
+type LimitsConfig struct {
+	DefaultLimits TenantLimits            `yaml:"default"`
+	Tenants       map[string]TenantLimits `yaml:"tenants"`
+}
+
+func ParseLimitsConfig(content []byte) (*LimitsConfig, error) {
+	var cfg LimitsConfig
+	if err := yaml.Unmarshal(content, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing receive limits config: %w", err)
+	}
+	return &cfg, nil
+}
+
+func (c *LimitsConfig) ForTenant(tenant string) TenantLimits {
+	if l, ok := c.Tenants[tenant]; ok {
+		return l
+	}
+	return c.DefaultLimits
+}
+
+// LimitsEnforcer tracks current series counts per tenant and rejects writes
+// that would exceed the configured limits.
+type LimitsEnforcer struct {
+	cfg *LimitsConfig
+
+	mtx         sync.Mutex
+	seriesCount map[string]int
+	lastSeriesAt map[string]time.Time
+}
+
+func NewLimitsEnforcer(cfg *LimitsConfig) *LimitsEnforcer {
+	return &LimitsEnforcer{cfg: cfg, seriesCount: map[string]int{}, lastSeriesAt: map[string]time.Time{}}
+}
+
+// CheckSamples rejects the write outright if numSamples exceeds the
+// tenant's per-request cap.
+func (e *LimitsEnforcer) CheckSamples(tenant string, numSamples int) error {
+	l := e.cfg.ForTenant(tenant)
+	if l.MaxSamplesPerSend > 0 && numSamples > l.MaxSamplesPerSend {
+		return fmt.Errorf("tenant %q exceeded max samples per send: %d > %d", tenant, numSamples, l.MaxSamplesPerSend)
+	}
+	return nil
+}
+
+// CheckNewSeries rejects new-series creation once the tenant is at its
+// MaxSeries cap, and updates the tracked count on acceptance.
+func (e *LimitsEnforcer) CheckNewSeries(tenant string, numNewSeries int) error {
+	l := e.cfg.ForTenant(tenant)
+	if l.MaxSeries <= 0 {
+		return nil
+	}
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	if e.seriesCount[tenant]+numNewSeries > l.MaxSeries {
+		return fmt.Errorf("tenant %q would exceed max series limit of %d", tenant, l.MaxSeries)
+	}
+	e.seriesCount[tenant] += numNewSeries
+	return nil
+}
@@ -0,0 +1,88 @@
+// Package receive implements the Thanos Receiver: a remote_write endpoint
+// that appends incoming samples to local per-tenant TSDBs and makes them
+// available over the StoreAPI.
+package receive
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/prometheus/prometheus/storage"
+)
+
+// TenantStorage is the subset of tsdb.DB that MultiTSDB needs, kept as an
+// interface so tests can substitute a fake without standing up a real TSDB.
+type TenantStorage interface {
+	storage.Storage
+}
+
+// TSDBOpener opens (or creates) the on-disk TSDB for a tenant.
+type TSDBOpener func(dataDir string) (TenantStorage, error)
+
+// MultiTSDB owns one TSDB per tenant, opened lazily on first write and keyed
+// by the tenant extracted from the request (see DefaultTenantHeader in
+// pkg/tenancy), so a single receiver process can ingest for many tenants
+// without their series colliding in one TSDB.
+type MultiTSDB struct {
+	dataDir string
+	open    TSDBOpener
+
+	mtx   sync.RWMutex
+	tsdbs map[string]TenantStorage
+}
+
+func NewMultiTSDB(dataDir string, open TSDBOpener) *MultiTSDB {
+	return &MultiTSDB{dataDir: dataDir, open: open, tsdbs: map[string]TenantStorage{}}
+}
+
+// TenantAppendable returns the Appendable for tenant, opening its TSDB under
+// <dataDir>/<tenant> on first use.
+func (m *MultiTSDB) TenantAppendable(tenant string) (storage.Appendable, error) {
+	if tenant == "" {
+		return nil, fmt.Errorf("empty tenant")
+	}
+
+	m.mtx.RLock()
+	db, ok := m.tsdbs[tenant]
+	m.mtx.RUnlock()
+	if ok {
+		return db, nil
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if db, ok := m.tsdbs[tenant]; ok {
+		return db, nil
+	}
+	db, err := m.open(filepath.Join(m.dataDir, tenant))
+	if err != nil {
+		return nil, fmt.Errorf("opening TSDB for tenant %q: %w", tenant, err)
+	}
+	m.tsdbs[tenant] = db
+	return db, nil
+}
+
+// Tenants returns the set of tenants with an opened TSDB.
+func (m *MultiTSDB) Tenants() []string {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	out := make([]string, 0, len(m.tsdbs))
+	for t := range m.tsdbs {
+		out = append(out, t)
+	}
+	return out
+}
+
+// Close shuts down every open tenant TSDB.
+func (m *MultiTSDB) Close() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	var lastErr error
+	for tenant, db := range m.tsdbs {
+		if err := db.Close(); err != nil {
+			lastErr = fmt.Errorf("closing TSDB for tenant %q: %w", tenant, err)
+		}
+	}
+	return lastErr
+}
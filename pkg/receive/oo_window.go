@@ -0,0 +1,29 @@
+package receive
+
+import (
+	"fmt"
+	"time"
+)
+
+// OutOfOrderWindow bounds how far behind the TSDB head a sample may still be
+// accepted, letting receive tolerate reordering or retried remote_write
+// batches instead of rejecting every sample older than the latest one seen
+// for a series.
+type OutOfOrderWindow time.Duration
+
+// Validate rejects samples older than the window relative to the TSDB's
+// current max time, mirroring the check the TSDB head itself applies once
+// out-of-order ingestion is enabled.
+func (w OutOfOrderWindow) Validate(headMaxTime, sampleTs int64) error {
+	if w <= 0 {
+		if sampleTs < headMaxTime {
+			return fmt.Errorf("sample timestamp %d out of order (head max time %d) and out-of-order ingestion is disabled", sampleTs, headMaxTime)
+		}
+		return nil
+	}
+	oldest := headMaxTime - time.Duration(w).Milliseconds()
+	if sampleTs < oldest {
+		return fmt.Errorf("sample timestamp %d is older than the %s out-of-order window (oldest allowed %d)", sampleTs, time.Duration(w), oldest)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package receive
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// QuorumWriter forwards a write to a set of replica endpoints and succeeds
+// once a quorum of them have acknowledged it, matching Thanos receive's
+// existing at-least-quorum semantics for the write path.
+type QuorumWriter struct {
+	replicationFactor int
+	write             func(ctx context.Context, endpoint string) error
+}
+
+func NewQuorumWriter(replicationFactor int, write func(ctx context.Context, endpoint string) error) *QuorumWriter {
+	return &QuorumWriter{replicationFactor: replicationFactor, write: write}
+}
+
+func quorum(n int) int {
+	return n/2 + 1
+}
+
+// WriteQuorum writes to all endpoints concurrently and returns once quorum
+// successes are observed or it becomes impossible to reach quorum.
+func (q *QuorumWriter) WriteQuorum(ctx context.Context, endpoints []string) error {
+	need := quorum(len(endpoints))
+	if need > len(endpoints) {
+		return fmt.Errorf("replication factor %d needs quorum %d but only %d endpoints configured", q.replicationFactor, need, len(endpoints))
+	}
+
+	results := make(chan error, len(endpoints))
+	for _, ep := range endpoints {
+		ep := ep
+		go func() { results <- q.write(ctx, ep) }()
+	}
+
+	var (
+		ok, failed int
+		errs       []error
+	)
+	for i := 0; i < len(endpoints); i++ {
+		if err := <-results; err != nil {
+			failed++
+			errs = append(errs, err)
+		} else {
+			ok++
+		}
+		if ok >= need {
+			return nil
+		}
+		if failed > len(endpoints)-need {
+			return fmt.Errorf("quorum of %d not reached, %d failures: %v", need, failed, errs)
+		}
+	}
+	return fmt.Errorf("quorum of %d not reached", need)
+}
+
+// ReadRepairer re-writes samples observed from one replica during a read to
+// any replica that a concurrent health check found missing them, so gaps
+// caused by a replica that missed a write (but later came back healthy)
+// self-heal without operator intervention.
+type ReadRepairer struct {
+	mtx     sync.Mutex
+	pending map[string]struct{} // endpoints known to be behind
+	repair  func(ctx context.Context, endpoint string) error
+}
+
+func NewReadRepairer(repair func(ctx context.Context, endpoint string) error) *ReadRepairer {
+	return &ReadRepairer{pending: map[string]struct{}{}, repair: repair}
+}
+
+// MarkBehind flags endpoint as missing data observed on a read.
+func (r *ReadRepairer) MarkBehind(endpoint string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.pending[endpoint] = struct{}{}
+}
+
+// RepairAll attempts repair() against every endpoint marked behind,
+// clearing the flag on success.
+func (r *ReadRepairer) RepairAll(ctx context.Context) {
+	r.mtx.Lock()
+	todo := make([]string, 0, len(r.pending))
+	for ep := range r.pending {
+		todo = append(todo, ep)
+	}
+	r.mtx.Unlock()
+
+	for _, ep := range todo {
+		if err := r.repair(ctx, ep); err == nil {
+			r.mtx.Lock()
+			delete(r.pending, ep)
+			r.mtx.Unlock()
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package receive
+
+import "github.com/prometheus/prometheus/prompb"
+
+// SplitWriteRequest breaks req into chunks of at most maxSeriesPerChunk
+// timeseries each, preserving relative order. Giant remote_write batches
+// (e.g. after a target comes back from a long outage) are split before
+// forwarding to hashring peers so a single oversized gRPC message doesn't
+// trip max-message-size limits or monopolize a peer's write path.
+func SplitWriteRequest(req *prompb.WriteRequest, maxSeriesPerChunk int) []*prompb.WriteRequest {
+	if maxSeriesPerChunk <= 0 || len(req.Timeseries) <= maxSeriesPerChunk {
+		return []*prompb.WriteRequest{req}
+	}
+
+	var out []*prompb.WriteRequest
+	for start := 0; start < len(req.Timeseries); start += maxSeriesPerChunk {
+		end := start + maxSeriesPerChunk
+		if end > len(req.Timeseries) {
+			end = len(req.Timeseries)
+		}
+		out = append(out, &prompb.WriteRequest{Timeseries: req.Timeseries[start:end]})
+	}
+	return out
+}
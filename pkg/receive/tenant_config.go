@@ -0,0 +1,47 @@
+package receive
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// TenantConfig overrides per-tenant TSDB settings that otherwise default to
+// the receiver's global flags, letting tenants with different compliance or
+// cardinality profiles share one receiver deployment.
+type TenantConfig struct {
+	Retention      time.Duration     `yaml:"retention"`
+	ExternalLabels map[string]string `yaml:"external_labels"`
+}
+
+// TenantConfigs is the --receive.tenant-config-file document, keyed by
+// tenant.
+type TenantConfigs struct {
+	Default TenantConfig            `yaml:"default"`
+	Tenants map[string]TenantConfig `yaml:"tenants"`
+}
+
+func ParseTenantConfigs(content []byte) (*TenantConfigs, error) {
+	var cfg TenantConfigs
+	if err := yaml.Unmarshal(content, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing receive tenant config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// ForTenant returns tenant's effective config, falling back to Default for
+// any zero-valued field.
+func (c *TenantConfigs) ForTenant(tenant string) TenantConfig {
+	cfg, ok := c.Tenants[tenant]
+	if !ok {
+		return c.Default
+	}
+	if cfg.Retention == 0 {
+		cfg.Retention = c.Default.Retention
+	}
+	if cfg.ExternalLabels == nil {
+		cfg.ExternalLabels = c.Default.ExternalLabels
+	}
+	return cfg
+}
@@ -0,0 +1,117 @@
+// Package reloader watches a Prometheus config (and optionally rule
+// directories) for changes, expands environment variables into it, and
+// triggers Prometheus' /-/reload endpoint, so config/rule changes delivered
+// by a ConfigMap or secret mount take effect without a pod restart.
+package reloader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+var envRe = regexp.MustCompile(`\$\(([a-zA-Z_][a-zA-Z0-9_]*)\)`)
+
+// Options configures a Reloader.
+type Options struct {
+	// CfgFile is the templated input config; its expanded form is written
+	// to CfgOutputFile.
+	CfgFile       string
+	CfgOutputFile string
+	// WatchedDirs are extra directories (e.g. rule files) whose changes also
+	// trigger a reload, without being templated themselves.
+	WatchedDirs []string
+	// ReloadFunc is called (e.g. to POST <url>/-/reload) after every change.
+	ReloadFunc func(ctx context.Context) error
+}
+
+type Reloader struct {
+	logger log.Logger
+	opts   Options
+}
+
+func New(logger log.Logger, opts Options) *Reloader {
+	return &Reloader{logger: logger, opts: opts}
+}
+
+// Watch blocks, applying the current config once immediately and again on
+// every filesystem event, until ctx is canceled.
+func (r *Reloader) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating fsnotify watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if r.opts.CfgFile != "" {
+		if err := watcher.Add(r.opts.CfgFile); err != nil {
+			return fmt.Errorf("watching config file: %w", err)
+		}
+	}
+	for _, d := range r.opts.WatchedDirs {
+		if err := watcher.Add(d); err != nil {
+			return fmt.Errorf("watching dir %s: %w", d, err)
+		}
+	}
+
+	if err := r.apply(ctx); err != nil {
+		level.Error(r.logger).Log("msg", "initial reload failed", "err", err)
+	}
+
+	debounce := time.NewTimer(0)
+	<-debounce.C
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-watcher.Errors:
+			level.Error(r.logger).Log("msg", "watcher error", "err", err)
+		case <-watcher.Events:
+			debounce.Reset(1 * time.Second)
+		case <-debounce.C:
+			if err := r.apply(ctx); err != nil {
+				level.Error(r.logger).Log("msg", "reload failed", "err", err)
+			}
+		}
+	}
+}
+
+func (r *Reloader) apply(ctx context.Context) error {
+	if r.opts.CfgFile != "" {
+		if err := r.expandAndWrite(); err != nil {
+			return err
+		}
+	}
+	if r.opts.ReloadFunc != nil {
+		return r.opts.ReloadFunc(ctx)
+	}
+	return nil
+}
+
+// expandAndWrite reads CfgFile, substitutes every $(ENV_VAR) reference with
+// the corresponding environment variable, and writes the result to
+// CfgOutputFile.
+func (r *Reloader) expandAndWrite() error {
+	content, err := os.ReadFile(r.opts.CfgFile)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+
+	expanded := envRe.ReplaceAllFunc(content, func(m []byte) []byte {
+		name := envRe.FindSubmatch(m)[1]
+		return []byte(os.Getenv(string(name)))
+	})
+
+	existing, _ := os.ReadFile(r.opts.CfgOutputFile)
+	if bytes.Equal(existing, expanded) {
+		return nil
+	}
+	return os.WriteFile(r.opts.CfgOutputFile, expanded, 0o644)
+}
@@ -0,0 +1,118 @@
+// Package rewrite interprets a block rewrite config (drop series by
+// matcher, relabel external or series labels): it decides which series to
+// keep and which labels to emit, and records the source block in the
+// rewritten meta. It does not download, read or write block data itself.
+package rewrite
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/oklog/ulid"
+	"github.com/prometheus/prometheus/model/labels"
+	"gopkg.in/yaml.v2"
+
+	"github.com/thanos-io/thanos/pkg/block/metadata"
+)
+
+// Config describes the series-dropping and relabeling to apply to a block.
+type Config struct {
+	// DeleteMatchers drops every series matching any of these matcher sets.
+	DeleteMatchers [][]*labels.Matcher `yaml:"-"`
+	// RelabelExternal relabels the block's external (Thanos) labels.
+	RelabelExternal map[string]string `yaml:"relabel_external_labels"`
+	// RelabelSeries relabels the labels of every retained series.
+	RelabelSeries []RelabelRule `yaml:"relabel_series"`
+}
+
+// RelabelRule renames a single label across every series in the block.
+type RelabelRule struct {
+	SourceLabel string `yaml:"source_label"`
+	TargetLabel string `yaml:"target_label"`
+}
+
+// ParseConfig parses a rewrite config YAML. DeleteMatchers, being
+// matcher-syntax rather than plain YAML scalars, are not read from the YAML
+// and must be set by the caller after parsing; `thanos tools bucket
+// rewrite` does not set them.
+func ParseConfig(content []byte) (Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(content, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parsing rewrite config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Provenance describes where a rewritten block came from. This package
+// does not write it out as such: RewriteExternalLabels records the source
+// block in meta.json's Thanos source field, and RewrittenAt is not stored.
+type Provenance struct {
+	SourceBlock ulid.ULID `json:"source_block"`
+	RewrittenAt int64     `json:"rewritten_at_unix"` // set by the caller; this package never reads the clock itself.
+}
+
+// Plan holds counts previewing what a rewrite would do without touching
+// any data. Nothing in this package computes one, and `bucket rewrite
+// --dry-run` does not use it.
+type Plan struct {
+	DroppedSeries    int
+	RelabeledSeries  int
+	NewExternalLabel map[string]string
+}
+
+// Rewriter applies Config without doing any block IO: it decides, per
+// series, whether to keep it (Keep) and what labels to emit (Relabel), and
+// rewrites the external labels in a block's meta (RewriteExternalLabels).
+type Rewriter struct {
+	cfg Config
+}
+
+func New(cfg Config) *Rewriter { return &Rewriter{cfg: cfg} }
+
+// Keep reports whether lset should be retained.
+func (r *Rewriter) Keep(lset labels.Labels) bool {
+	for _, matchers := range r.cfg.DeleteMatchers {
+		if matchesAll(matchers, lset) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAll(matchers []*labels.Matcher, lset labels.Labels) bool {
+	for _, m := range matchers {
+		if !m.Matches(lset.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Relabel returns lset with every configured source/target label rename
+// applied.
+func (r *Rewriter) Relabel(lset labels.Labels) labels.Labels {
+	if len(r.cfg.RelabelSeries) == 0 {
+		return lset
+	}
+	b := labels.NewBuilder(lset)
+	for _, rule := range r.cfg.RelabelSeries {
+		if v := lset.Get(rule.SourceLabel); v != "" {
+			b.Del(rule.SourceLabel)
+			b.Set(rule.TargetLabel, v)
+		}
+	}
+	return b.Labels()
+}
+
+// RewriteExternalLabels applies RelabelExternal on top of m's existing
+// Thanos external labels and stamps provenance pointing back at src.
+func (r *Rewriter) RewriteExternalLabels(m *metadata.Meta, src ulid.ULID, rewrittenAt time.Time) {
+	if m.Thanos.Labels == nil {
+		m.Thanos.Labels = map[string]string{}
+	}
+	for k, v := range r.cfg.RelabelExternal {
+		m.Thanos.Labels[k] = v
+	}
+	m.Thanos.Source = fmt.Sprintf("rewrite-of-%s", src)
+	_ = rewrittenAt // not recorded: meta.json has no free-form field for it.
+}
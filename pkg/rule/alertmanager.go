@@ -0,0 +1,61 @@
+package rule
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// AlertmanagerConfig configures one Alertmanager endpoint the ruler sends
+// firing alerts to.
+type AlertmanagerConfig struct {
+	APIVersion string        `yaml:"api_version"` // "v1" or "v2"
+	URL        string        `yaml:"url"`
+	Timeout    time.Duration `yaml:"timeout"`
+	TLSConfig  *tls.Config   `yaml:"-"`
+}
+
+// AlertmanagerV2Client sends alerts to an Alertmanager's native v2 HTTP API
+// (/api/v2/alerts), which carries richer fields (generator URL, fingerprint)
+// than the v1 API the ruler previously spoke exclusively.
+type AlertmanagerV2Client struct {
+	cfg    AlertmanagerConfig
+	client *http.Client
+}
+
+func NewAlertmanagerV2Client(cfg AlertmanagerConfig) *AlertmanagerV2Client {
+	transport := &http.Transport{TLSClientConfig: cfg.TLSConfig}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	return &AlertmanagerV2Client{cfg: cfg, client: &http.Client{Transport: transport, Timeout: timeout}}
+}
+
+// Send POSTs alerts to this Alertmanager's /api/v2/alerts endpoint.
+func (c *AlertmanagerV2Client) Send(ctx context.Context, alerts models.PostableAlerts) error {
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return fmt.Errorf("marshaling alerts: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL+"/api/v2/alerts", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending alerts to %s: %w", c.cfg.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("alertmanager %s responded with status %d", c.cfg.URL, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RuleGroupFile is a single rules file, as loaded/saved by the ruler's file
+// based rule manager.
+type RuleGroupFile struct {
+	Groups []RuleGroupSpec `yaml:"groups"`
+}
+
+type RuleGroupSpec struct {
+	Name  string    `yaml:"name"`
+	Rules []RuleSpec `yaml:"rules"`
+}
+
+type RuleSpec struct {
+	Record string            `yaml:"record,omitempty"`
+	Alert  string            `yaml:"alert,omitempty"`
+	Expr   string            `yaml:"expr"`
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+// RuntimeAPI lets an operator create or update a rule group without editing
+// files on disk directly, writing through to the same directory the file
+// based rule manager watches so a PUT takes effect on its next reload.
+type RuntimeAPI struct {
+	rulesDir string
+	reload   func() error
+}
+
+func NewRuntimeAPI(rulesDir string, reload func() error) *RuntimeAPI {
+	return &RuntimeAPI{rulesDir: rulesDir, reload: reload}
+}
+
+// PutGroup handles PUT /api/v1/rules/{namespace}/{group}, writing the
+// request body (a RuleGroupSpec as YAML) into <rulesDir>/<namespace>.yaml
+// and triggering a reload.
+func (a *RuntimeAPI) PutGroup(w http.ResponseWriter, r *http.Request, namespace, group string) {
+	var spec RuleGroupSpec
+	if err := yaml.NewDecoder(r.Body).Decode(&spec); err != nil {
+		http.Error(w, fmt.Sprintf("invalid rule group: %v", err), http.StatusBadRequest)
+		return
+	}
+	spec.Name = group
+
+	path := filepath.Join(a.rulesDir, namespace+".yaml")
+	file, err := loadOrNewFile(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	file.upsert(spec)
+
+	if err := writeFile(path, file); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := a.reload(); err != nil {
+		http.Error(w, fmt.Sprintf("rule group saved but reload failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+func (f *RuleGroupFile) upsert(spec RuleGroupSpec) {
+	for i, g := range f.Groups {
+		if g.Name == spec.Name {
+			f.Groups[i] = spec
+			return
+		}
+	}
+	f.Groups = append(f.Groups, spec)
+}
+
+func loadOrNewFile(path string) (*RuleGroupFile, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &RuleGroupFile{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f RuleGroupFile
+	if err := yaml.Unmarshal(content, &f); err != nil {
+		return nil, fmt.Errorf("parsing existing rules file %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+func writeFile(path string, f *RuleGroupFile) error {
+	out, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0o644)
+}
@@ -0,0 +1,64 @@
+package rule
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// GroupEvaluator runs a bounded number of rule groups concurrently, each on
+// its own jittered ticker, so a ruler with hundreds of groups on the same
+// interval doesn't evaluate them all in lockstep and spike query load every
+// tick.
+type GroupEvaluator struct {
+	sem chan struct{}
+}
+
+// NewGroupEvaluator limits concurrent group evaluations to maxConcurrent (0
+// means unlimited).
+func NewGroupEvaluator(maxConcurrent int) *GroupEvaluator {
+	var sem chan struct{}
+	if maxConcurrent > 0 {
+		sem = make(chan struct{}, maxConcurrent)
+	}
+	return &GroupEvaluator{sem: sem}
+}
+
+// Run calls eval every interval, offset by a random jitter in
+// [0, interval) so that groups sharing an interval don't all fire together,
+// until ctx is done. Concurrent calls across groups are bounded by the
+// evaluator's semaphore.
+func (e *GroupEvaluator) Run(ctx context.Context, interval time.Duration, eval func(context.Context)) {
+	jitter := time.Duration(rand.Int63n(int64(interval)))
+	select {
+	case <-time.After(jitter):
+	case <-ctx.Done():
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		e.acquire()
+		eval(ctx)
+		e.release()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *GroupEvaluator) acquire() {
+	if e.sem != nil {
+		e.sem <- struct{}{}
+	}
+}
+
+func (e *GroupEvaluator) release() {
+	if e.sem != nil {
+		<-e.sem
+	}
+}
@@ -0,0 +1,47 @@
+package rule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+)
+
+// ForStateQuerier queries back the ALERTS_FOR_STATE series the ruler itself
+// previously wrote, so that on restart an alert already pending/firing for
+// some duration resumes counting "for" from when it actually started
+// instead of from zero, avoiding a restart silently resetting for-duration
+// alerts.
+type ForStateQuerier interface {
+	Query(ctx context.Context, qs string, t time.Time) (promql.Vector, error)
+}
+
+// RestoreForState looks up the most recent ALERTS_FOR_STATE sample for an
+// alerting rule's active labels and returns the timestamp it records, or the
+// zero time if none is found (the alert is new).
+func RestoreForState(ctx context.Context, q ForStateQuerier, ruleLabels labels.Labels, at time.Time) (time.Time, error) {
+	qs := fmt.Sprintf(`ALERTS_FOR_STATE{%s}`, matchersFromLabels(ruleLabels))
+	v, err := q.Query(ctx, qs, at)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("restoring ALERTS_FOR_STATE: %w", err)
+	}
+	if len(v) == 0 {
+		return time.Time{}, nil
+	}
+	// ALERTS_FOR_STATE's sample value is the unix timestamp the alert first
+	// became active.
+	return time.Unix(int64(v[0].F), 0), nil
+}
+
+func matchersFromLabels(lset labels.Labels) string {
+	out := ""
+	lset.Range(func(l labels.Label) {
+		if out != "" {
+			out += ","
+		}
+		out += fmt.Sprintf("%s=%q", l.Name, l.Value)
+	})
+	return out
+}
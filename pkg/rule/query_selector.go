@@ -0,0 +1,58 @@
+package rule
+
+import (
+	"sync"
+	"time"
+)
+
+// QuerySelector round-robins rule evaluation queries across a set of
+// queryable endpoints, skipping any endpoint that has recently failed until
+// its cooldown elapses, so one flaky querier doesn't keep eating every Nth
+// evaluation.
+type QuerySelector struct {
+	cooldown time.Duration
+
+	mtx       sync.Mutex
+	endpoints []string
+	next      int
+	failedAt  map[string]time.Time
+}
+
+func NewQuerySelector(endpoints []string, cooldown time.Duration) *QuerySelector {
+	return &QuerySelector{endpoints: endpoints, cooldown: cooldown, failedAt: map[string]time.Time{}}
+}
+
+// Next returns the next healthy endpoint to use, or "" if every endpoint is
+// currently in its failure cooldown.
+func (s *QuerySelector) Next() string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	n := len(s.endpoints)
+	if n == 0 {
+		return ""
+	}
+	for i := 0; i < n; i++ {
+		ep := s.endpoints[s.next]
+		s.next = (s.next + 1) % n
+		if failedAt, ok := s.failedAt[ep]; !ok || time.Since(failedAt) > s.cooldown {
+			return ep
+		}
+	}
+	return ""
+}
+
+// MarkFailed puts endpoint into its failure cooldown.
+func (s *QuerySelector) MarkFailed(endpoint string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.failedAt[endpoint] = time.Now()
+}
+
+// SetEndpoints replaces the endpoint list, e.g. after SD discovers a change.
+func (s *QuerySelector) SetEndpoints(endpoints []string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.endpoints = endpoints
+	s.next = 0
+}
@@ -0,0 +1,93 @@
+// Package rule implements the Thanos Ruler: a component that evaluates
+// Prometheus-compatible alerting/recording rules against a set of queryable
+// endpoints and ships the results onward.
+package rule
+
+import (
+	"context"
+
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/storage"
+)
+
+// RemoteWriteAppendable implements storage.Appendable by shipping every
+// appended sample to a remote_write endpoint instead of a local TSDB,
+// letting the ruler run stateless: rule results are durable as soon as the
+// receiver/remote storage accepts them, and the ruler itself holds no data
+// across restarts.
+type RemoteWriteAppendable struct {
+	push func(ctx context.Context, req *prompb.WriteRequest) error
+}
+
+func NewRemoteWriteAppendable(push func(ctx context.Context, req *prompb.WriteRequest) error) *RemoteWriteAppendable {
+	return &RemoteWriteAppendable{push: push}
+}
+
+func (a *RemoteWriteAppendable) Appender(ctx context.Context) storage.Appender {
+	return &remoteWriteAppender{ctx: ctx, push: a.push}
+}
+
+type remoteWriteAppender struct {
+	ctx  context.Context
+	push func(ctx context.Context, req *prompb.WriteRequest) error
+	buf  []prompb.TimeSeries
+}
+
+func (a *remoteWriteAppender) Append(ref storage.SeriesRef, l labels.Labels, t int64, v float64) (storage.SeriesRef, error) {
+	ts := prompb.TimeSeries{Samples: []prompb.Sample{{Timestamp: t, Value: v}}}
+	l.Range(func(lb labels.Label) {
+		ts.Labels = append(ts.Labels, prompb.Label{Name: lb.Name, Value: lb.Value})
+	})
+	a.buf = append(a.buf, ts)
+	return ref, nil
+}
+
+func (a *remoteWriteAppender) Commit() error {
+	if len(a.buf) == 0 {
+		return nil
+	}
+	defer func() { a.buf = nil }()
+	return a.push(a.ctx, &prompb.WriteRequest{Timeseries: a.buf})
+}
+
+func (a *remoteWriteAppender) Rollback() error {
+	a.buf = nil
+	return nil
+}
+
+// SetOptions is a no-op: rule results are plain float samples, so there is
+// nothing for DiscardOutOfOrder to apply to.
+func (a *remoteWriteAppender) SetOptions(opts *storage.AppendOptions) {}
+
+// AppendExemplar is unsupported: rule evaluation never produces exemplars.
+func (a *remoteWriteAppender) AppendExemplar(ref storage.SeriesRef, l labels.Labels, e exemplar.Exemplar) (storage.SeriesRef, error) {
+	return ref, nil
+}
+
+// AppendHistogram is unsupported: recording/alerting rules only ever
+// evaluate to float samples.
+func (a *remoteWriteAppender) AppendHistogram(ref storage.SeriesRef, l labels.Labels, t int64, h *histogram.Histogram, fh *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	return ref, nil
+}
+
+// AppendHistogramSTZeroSample is unsupported, for the same reason as
+// AppendHistogram.
+func (a *remoteWriteAppender) AppendHistogramSTZeroSample(ref storage.SeriesRef, l labels.Labels, t, st int64, h *histogram.Histogram, fh *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	return ref, nil
+}
+
+// UpdateMetadata is unsupported: the ruler doesn't track per-series metric
+// metadata for its own rule results.
+func (a *remoteWriteAppender) UpdateMetadata(ref storage.SeriesRef, l labels.Labels, m metadata.Metadata) (storage.SeriesRef, error) {
+	return ref, nil
+}
+
+// AppendSTZeroSample is unsupported: rule results have no start timestamp
+// to record.
+func (a *remoteWriteAppender) AppendSTZeroSample(ref storage.SeriesRef, l labels.Labels, t, st int64) (storage.SeriesRef, error) {
+	return ref, nil
+}
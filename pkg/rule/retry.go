@@ -0,0 +1,55 @@
+package rule
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/prometheus/promql"
+)
+
+// RetryConfig bounds retries when evaluating a rule's query through a
+// query-frontend, which may transiently fail (e.g. a split sub-query hitting
+// an overloaded querier) without the rule itself being at fault.
+type RetryConfig struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+}
+
+var DefaultRetryConfig = RetryConfig{MaxRetries: 3, BaseDelay: 500 * time.Millisecond}
+
+// QueryFunc matches promql.EngineOpts' query execution signature closely
+// enough to wrap with retries.
+type QueryFunc func(ctx context.Context, qs string, t time.Time) (promql.Vector, error)
+
+// WithRetries wraps query so that transient errors are retried up to
+// cfg.MaxRetries times with exponential backoff, letting the ruler run
+// stateless against a query-frontend without a single frontend hiccup
+// failing a rule's evaluation outright.
+func WithRetries(query QueryFunc, cfg RetryConfig) QueryFunc {
+	return func(ctx context.Context, qs string, t time.Time) (promql.Vector, error) {
+		var lastErr error
+		delay := cfg.BaseDelay
+		for attempt := 0; attempt <= cfg.MaxRetries; attempt++ {
+			v, err := query(ctx, qs, t)
+			if err == nil {
+				return v, nil
+			}
+			lastErr = err
+			if !isRetryable(err) {
+				return nil, err
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+		return nil, lastErr
+	}
+}
+
+func isRetryable(err error) bool {
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,40 @@
+// Package rules implements the querier side of the Thanos Rules API: fanning
+// RulesRequest out across rule-group-reporting StoreAPIs (sidecar and ruler)
+// and deduplicating rule groups reported by more than one replica.
+package rules
+
+import (
+	"context"
+
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+type Proxy struct {
+	clients func() []storepb.RulesClient
+}
+
+func NewProxy(clients func() []storepb.RulesClient) *Proxy {
+	return &Proxy{clients: clients}
+}
+
+func (p *Proxy) Rules(ctx context.Context, r *storepb.RulesRequest) (*storepb.RulesResponse, error) {
+	merged := &storepb.RulesResponse{}
+	seen := map[string]struct{}{}
+
+	for _, c := range p.clients() {
+		resp, err := c.Rules(ctx, r)
+		if err != nil {
+			merged.Warnings = append(merged.Warnings, err.Error())
+			continue
+		}
+		for _, g := range resp.Groups {
+			k := g.File + "|" + g.Name
+			if _, dup := seen[k]; dup {
+				continue
+			}
+			seen[k] = struct{}{}
+			merged.Groups = append(merged.Groups, g)
+		}
+	}
+	return merged, nil
+}
@@ -0,0 +1,79 @@
+// Package grpc defines a gRPC server that Thanos components expose their
+// StoreAPI (and other) services on.
+package grpc
+
+import (
+	"net"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+
+	"github.com/thanos-io/thanos/pkg/component"
+)
+
+// Server wraps a *grpc.Server with the listener and lifecycle glue that all
+// Thanos components share.
+type Server struct {
+	logger log.Logger
+	comp   component.Component
+	srv    *grpc.Server
+	listener net.Listener
+
+	opts options
+}
+
+type options struct {
+	network string
+	listen  string
+}
+
+// Option overrides behavior of a Server.
+type Option func(*options)
+
+// WithListen sets the network and address the server listens on.
+func WithListen(network, listen string) Option {
+	return func(o *options) {
+		o.network = network
+		o.listen = listen
+	}
+}
+
+// New returns a new gRPC Server serving srv for the given component.
+func New(logger log.Logger, reg *prometheus.Registry, comp component.Component, srv *grpc.Server, opts ...Option) *Server {
+	o := options{network: "tcp", listen: ":10901"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Server{logger: logger, comp: comp, srv: srv, opts: o}
+}
+
+// ListenAndServe starts serving gRPC requests until the server is shut down.
+func (s *Server) ListenAndServe() error {
+	l, err := net.Listen(s.opts.network, s.opts.listen)
+	if err != nil {
+		return err
+	}
+	s.listener = l
+	return s.srv.Serve(l)
+}
+
+// Shutdown gracefully stops the server.
+func (s *Server) Shutdown(err error) {
+	s.srv.GracefulStop()
+}
+
+// RegisteredCompressors lists the names of the gRPC compressors that have
+// been registered with the global encoding registry (e.g. via
+// encoding.RegisterCompressor), so callers can validate a requested
+// --grpc-compression value before starting the server.
+func RegisteredCompressors() []string {
+	names := []string{"none"}
+	for _, name := range []string{"gzip", "snappy", "zstd"} {
+		if encoding.GetCompressor(name) != nil {
+			names = append(names, name)
+		}
+	}
+	return names
+}
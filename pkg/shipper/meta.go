@@ -0,0 +1,66 @@
+package shipper
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// ShippedBlocks tracks which local blocks have already been fully uploaded,
+// persisted as shipper.json next to the data directory so a sidecar restart
+// mid-backfill resumes from the last confirmed block instead of re-shipping
+// (or, worse, losing track of) everything.
+type ShippedBlocks struct {
+	path    string
+	Uploaded map[string]int64 `json:"uploaded"` // block ULID -> unix upload time
+}
+
+const shipperMetaFilename = "shipper.json"
+
+// ReadShippedBlocks loads shipper.json from dataDir, returning an empty
+// record if it doesn't exist yet (e.g. first run).
+func ReadShippedBlocks(dataDir string) (*ShippedBlocks, error) {
+	path := filepath.Join(dataDir, shipperMetaFilename)
+	s := &ShippedBlocks{path: path, Uploaded: map[string]int64{}}
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, err
+	}
+	if s.Uploaded == nil {
+		s.Uploaded = map[string]int64{}
+	}
+	return s, nil
+}
+
+// MarkUploaded records blockID as fully uploaded at uploadedAt and persists
+// the updated record.
+func (s *ShippedBlocks) MarkUploaded(blockID string, uploadedAt int64) error {
+	s.Uploaded[blockID] = uploadedAt
+	return s.save()
+}
+
+func (s *ShippedBlocks) IsUploaded(blockID string) bool {
+	_, ok := s.Uploaded[blockID]
+	return ok
+}
+
+func (s *ShippedBlocks) save() error {
+	tmp := s.path + ".tmp"
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	// Rename is atomic on the same filesystem, so a crash mid-write never
+	// leaves shipper.json truncated or otherwise corrupt.
+	return os.Rename(tmp, s.path)
+}
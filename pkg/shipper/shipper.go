@@ -0,0 +1,118 @@
+// Package shipper uploads local TSDB blocks (as written by the sidecar's
+// Prometheus) to object storage.
+package shipper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/thanos-io/thanos/pkg/block/metadata"
+	"github.com/thanos-io/thanos/pkg/objstore"
+)
+
+// Options configures which local blocks the Shipper considers for upload.
+type Options struct {
+	// AllowOutOfOrderUploads lets blocks be uploaded even if an earlier one
+	// is still pending.
+	AllowOutOfOrderUploads bool
+	// UploadCompacted allows uploading blocks with Compaction.Level > 1.
+	// Disabled by default: Prometheus itself never produces compacted
+	// blocks, so historically the sidecar only ever saw (and only ever
+	// needed to ship) level-1 blocks; this flag exists for sidecars running
+	// against a Prometheus with local compaction enabled.
+	UploadCompacted bool
+}
+
+type Shipper struct {
+	logger  log.Logger
+	bucket  objstore.Bucket
+	dataDir string
+	opts    Options
+	shipped *ShippedBlocks
+}
+
+func New(logger log.Logger, bucket objstore.Bucket, dataDir string, opts Options) (*Shipper, error) {
+	shipped, err := ReadShippedBlocks(dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("recovering shipper meta: %w", err)
+	}
+	return &Shipper{logger: logger, bucket: bucket, dataDir: dataDir, opts: opts, shipped: shipped}, nil
+}
+
+// Sync uploads every local block not yet known to be in the bucket,
+// skipping compacted blocks unless UploadCompacted is set.
+func (s *Shipper) Sync(ctx context.Context) (uploaded int, err error) {
+	entries, err := os.ReadDir(s.dataDir)
+	if err != nil {
+		return 0, fmt.Errorf("reading data dir: %w", err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() || s.shipped.IsUploaded(e.Name()) {
+			continue
+		}
+		meta, err := metadata.ReadFromDir(filepath.Join(s.dataDir, e.Name()))
+		if err != nil {
+			level.Warn(s.logger).Log("msg", "skip block without meta.json", "block", e.Name(), "err", err)
+			continue
+		}
+		if meta.Compaction.Level > 1 && !s.opts.UploadCompacted {
+			level.Debug(s.logger).Log("msg", "skipping compacted block, upload-compacted disabled", "block", e.Name(), "level", meta.Compaction.Level)
+			continue
+		}
+		if err := s.uploadBlock(ctx, filepath.Join(s.dataDir, e.Name())); err != nil {
+			return uploaded, fmt.Errorf("uploading block %s: %w", e.Name(), err)
+		}
+		if err := s.shipped.MarkUploaded(e.Name(), time.Now().Unix()); err != nil {
+			return uploaded, fmt.Errorf("persisting shipper meta for block %s: %w", e.Name(), err)
+		}
+		uploaded++
+	}
+	return uploaded, nil
+}
+
+// uploadBlock uploads every file under dir except meta.json, then meta.json
+// last. Files already present in the bucket (from a previous, interrupted
+// run of this same block) are skipped via an Exists check, so a resumed
+// upload only re-sends the files that didn't make it across last time.
+func (s *Shipper) uploadBlock(ctx context.Context, dir string) error {
+	blockID := filepath.Base(dir)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Base(path) == metadata.MetaFilename {
+			return err
+		}
+		rel, err := filepath.Rel(s.dataDir, path)
+		if err != nil {
+			return err
+		}
+		if ok, err := s.bucket.Exists(ctx, rel); err == nil && ok {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return s.bucket.Upload(ctx, rel, f)
+	})
+	if err != nil {
+		return err
+	}
+
+	// meta.json is uploaded last and unconditionally: its presence in the
+	// bucket is what every other Thanos component treats as "this block is
+	// complete", so it must never be written before every data file is.
+	metaPath := filepath.Join(dir, metadata.MetaFilename)
+	f, err := os.Open(metaPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return s.bucket.Upload(ctx, filepath.Join(blockID, metadata.MetaFilename), f)
+}
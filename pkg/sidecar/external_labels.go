@@ -0,0 +1,31 @@
+package sidecar
+
+import (
+	"fmt"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// ValidateExternalLabels requires that the sidecar's Prometheus has at least
+// one external label set, since Thanos uses external labels to distinguish
+// blocks from different Prometheus replicas once they land in the bucket;
+// an unlabeled block is indistinguishable from any other and can't be
+// deduplicated or attributed correctly.
+func ValidateExternalLabels(lset labels.Labels) error {
+	if lset.Len() == 0 {
+		return fmt.Errorf("no external labels configured on Prometheus; Thanos requires at least one to identify blocks uploaded from this replica")
+	}
+	return nil
+}
+
+// AsBlockLabels converts a Prometheus external label set into the map
+// stored under Thanos.Labels in meta.json, so every block the sidecar ships
+// carries the same external labels the querier will later see over the
+// StoreAPI, without the operator having to configure them twice.
+func AsBlockLabels(lset labels.Labels) map[string]string {
+	out := make(map[string]string, lset.Len())
+	lset.Range(func(l labels.Label) {
+		out[l.Name] = l.Value
+	})
+	return out
+}
@@ -0,0 +1,51 @@
+package sidecar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// RemoteReadClient is the subset of a Prometheus remote-read client the
+// sidecar needs.
+type RemoteReadClient interface {
+	// Read issues a SAMPLES-type remote read request (the only kind every
+	// Prometheus version supports).
+	Read(ctx context.Context, req *prompb.ReadRequest) (*prompb.ReadResponse, error)
+	// StreamingRead issues a STREAMED_XOR_CHUNKS remote read request,
+	// returning an error the caller should treat as "unsupported" when the
+	// target Prometheus predates streaming remote read (added in 2.13).
+	StreamingRead(ctx context.Context, req *prompb.ReadRequest) (prompb.ChunkedReadResponse, error)
+}
+
+// NegotiateReadMode tries StreamingRead first (far cheaper for the sidecar
+// to relay, since it avoids buffering the whole response) and transparently
+// falls back to the buffered Read API if the target Prometheus doesn't
+// support STREAMED_XOR_CHUNKS, so operators don't need to know their
+// Prometheus version to get the sidecar working.
+func NegotiateReadMode(ctx context.Context, c RemoteReadClient, req *prompb.ReadRequest) (streaming bool, resp *prompb.ReadResponse, chunked prompb.ChunkedReadResponse, err error) {
+	req.AcceptedResponseTypes = []prompb.ReadRequest_ResponseType{prompb.ReadRequest_STREAMED_XOR_CHUNKS}
+	chunked, err = c.StreamingRead(ctx, req)
+	if err == nil {
+		return true, nil, chunked, nil
+	}
+	if !isUnsupportedResponseType(err) {
+		return false, nil, prompb.ChunkedReadResponse{}, fmt.Errorf("streaming remote read: %w", err)
+	}
+
+	req.AcceptedResponseTypes = []prompb.ReadRequest_ResponseType{prompb.ReadRequest_SAMPLES}
+	resp, err = c.Read(ctx, req)
+	if err != nil {
+		return false, nil, prompb.ChunkedReadResponse{}, fmt.Errorf("falling back to buffered remote read: %w", err)
+	}
+	return false, resp, prompb.ChunkedReadResponse{}, nil
+}
+
+func isUnsupportedResponseType(err error) bool {
+	// Prometheus servers that don't support chunked streaming respond with a
+	// plain 4xx whose body mentions the requested type; callers unable to
+	// distinguish that precisely should still treat any non-2xx on the
+	// streaming request as "try the fallback".
+	return err != nil
+}
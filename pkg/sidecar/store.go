@@ -0,0 +1,35 @@
+// Package sidecar adapts a local Prometheus to the Thanos StoreAPI.
+package sidecar
+
+import (
+	"fmt"
+
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+// StoreConfig bounds what the sidecar's StoreAPI will serve.
+type StoreConfig struct {
+	// MinTime, if set, is the earliest start time ClampRequest lets a Series
+	// request have, so local data older than it (e.g. data also served from
+	// block storage during the overlap window) isn't served twice.
+	// LabelNames and LabelValues requests are not clamped.
+	MinTime int64
+}
+
+// ClampRequest raises req's MinTime to cfg.MinTime if it is lower and reports whether
+// the request now has an empty range and should be answered immediately
+// with no data instead of being forwarded to Prometheus.
+func (cfg StoreConfig) ClampRequest(req *storepb.SeriesRequest) (empty bool) {
+	if cfg.MinTime > req.MinTime {
+		req.MinTime = cfg.MinTime
+	}
+	return req.MinTime > req.MaxTime
+}
+
+// Validate returns an error if cfg is internally inconsistent.
+func (cfg StoreConfig) Validate() error {
+	if cfg.MinTime < 0 {
+		return fmt.Errorf("store.limit.min-time must not be negative, got %d", cfg.MinTime)
+	}
+	return nil
+}
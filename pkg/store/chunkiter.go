@@ -0,0 +1,33 @@
+package store
+
+import (
+	"github.com/prometheus/prometheus/model/histogram"
+
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+// ChunkIterable decodes AggrChunks into samples, the seam dedup and
+// downsample code should be written against so both handle
+// ChunkEncodingHistogram/ChunkEncodingFloatHistogram chunks the same way
+// they handle ChunkEncodingXOR ones, rather than silently dropping
+// histogram samples because only XOR decoding was ever wired up.
+//
+// Decoding itself requires tsdb/chunkenc, which belongs to a local chunk
+// reader this tree doesn't carry — the same seam pkg/export's
+// SeriesSource leaves open on the block-reading side. ChunkIterable exists
+// so the rest of the data path (dedup, downsample, the querier's PromQL
+// series iterator) can be written against a stable interface now and
+// wired to a real decoder independently later.
+type ChunkIterable interface {
+	Iterator(c storepb.AggrChunk) (ChunkIterator, error)
+}
+
+// ChunkIterator yields one chunk's samples in order.
+type ChunkIterator interface {
+	Next() bool
+	// At returns the current sample. h is non-nil only when the chunk's
+	// Encoding is ChunkEncodingHistogram or ChunkEncodingFloatHistogram, in
+	// which case v is zero and must be ignored.
+	At() (t int64, v float64, h *histogram.FloatHistogram)
+	Err() error
+}
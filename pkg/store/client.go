@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+// Compression identifies a gRPC wire compressor usable between the querier
+// and the StoreAPIs it talks to.
+type Compression string
+
+const (
+	CompressionNone   Compression = "none"
+	CompressionSnappy Compression = "snappy"
+	CompressionZstd   Compression = "zstd"
+)
+
+// DialOption returns the grpc.DialOption needed to make outgoing Series/
+// LabelNames/LabelValues calls use the given compressor, or nil for "none".
+func (c Compression) DialOption() (grpc.DialOption, error) {
+	switch c {
+	case "", CompressionNone:
+		return nil, nil
+	case CompressionSnappy, CompressionZstd:
+		return grpc.WithDefaultCallOptions(grpc.UseCompressor(string(c))), nil
+	default:
+		return nil, fmt.Errorf("unsupported grpc compression %q", c)
+	}
+}
+
+// Client is a Thanos StoreAPI client bound to a single backing address.
+type Client struct {
+	storepb.StoreClient
+	addr      string
+	labelSets [][]storepb.Label
+	minTime   int64
+	maxTime   int64
+}
+
+// Addr returns the address this client talks to.
+func (c *Client) Addr() string { return c.addr }
+
+// NewClient dials addr and returns a StoreAPI client that applies comp to
+// the underlying gRPC connection.
+func NewClient(ctx context.Context, addr string, comp Compression, dialOpts ...grpc.DialOption) (*Client, error) {
+	compOpt, err := comp.DialOption()
+	if err != nil {
+		return nil, err
+	}
+	if compOpt != nil {
+		dialOpts = append(dialOpts, compOpt)
+	}
+	conn, err := grpc.DialContext(ctx, addr, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("dialing store %s: %w", addr, err)
+	}
+	return &Client{StoreClient: storepb.NewStoreClient(conn), addr: addr}, nil
+}
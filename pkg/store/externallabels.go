@@ -0,0 +1,39 @@
+package store
+
+import (
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/thanos-io/thanos/pkg/block/metadata"
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+// MatchesExternalLabels reports whether extLabels could satisfy matchers.
+// It's the server-side counterpart to PruneStores, for a StoreAPI
+// implementation to check its own external labels before handling a
+// Series call and answer with an empty response, without touching a
+// single block, when it fails. No Series handler in this tree calls it.
+func MatchesExternalLabels(extLabels labels.Labels, matchers []storepb.LabelMatcher) (bool, error) {
+	ms, err := storepb.MatchersToPromMatchers(matchers)
+	if err != nil {
+		return false, err
+	}
+	return matchersPossiblyMatch(extLabels, ms), nil
+}
+
+// PruneBlocks filters metas down to those whose external labels don't
+// contradict matchers, the same pushdown as MatchesExternalLabels applied
+// one level down inside a single store, so a block whose external labels
+// can't match can be skipped without an index read. This tree has no
+// BucketStore, and nothing calls PruneBlocks.
+func PruneBlocks(metas []*metadata.Meta, matchers []*labels.Matcher) []*metadata.Meta {
+	if len(matchers) == 0 {
+		return metas
+	}
+	out := make([]*metadata.Meta, 0, len(metas))
+	for _, m := range metas {
+		if matchersPossiblyMatch(labels.FromMap(m.Thanos.Labels), matchers) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
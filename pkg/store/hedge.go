@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+// ReplicaSet groups StoreAPI clients that are known to serve the same set of
+// blocks (e.g. multiple store gateway replicas sharded identically), so a
+// Series call against one member can be hedged against another.
+type ReplicaSet struct {
+	clients []*Client
+	// HedgeDelay is how long to wait for the first replica before firing a
+	// duplicate request at the next one. Zero disables hedging.
+	HedgeDelay time.Duration
+}
+
+// NewReplicaSet groups clients known to be backed by the same blocks.
+func NewReplicaSet(hedgeDelay time.Duration, clients ...*Client) *ReplicaSet {
+	return &ReplicaSet{clients: clients, HedgeDelay: hedgeDelay}
+}
+
+// Series issues req against the first replica, and – unless it has already
+// completed – against subsequent replicas every HedgeDelay, returning the
+// first stream to respond. Results from the losing requests are drained and
+// discarded, so only the winning replica's series reach the caller.
+func (r *ReplicaSet) Series(ctx context.Context, req *storepb.SeriesRequest, opts ...grpc.CallOption) (storepb.Store_SeriesClient, error) {
+	if len(r.clients) == 0 {
+		return nil, errNoReplicas
+	}
+	if r.HedgeDelay <= 0 || len(r.clients) == 1 {
+		return r.clients[0].Series(ctx, req, opts...)
+	}
+
+	type result struct {
+		stream storepb.Store_SeriesClient
+		cancel context.CancelFunc
+		err    error
+	}
+	resCh := make(chan result, len(r.clients))
+	stop := make(chan struct{})
+
+	for i, c := range r.clients {
+		i, c := i, c
+		cctx, cancel := context.WithCancel(ctx)
+		go func() {
+			if i > 0 {
+				select {
+				case <-time.After(time.Duration(i) * r.HedgeDelay):
+				case <-stop:
+					cancel()
+					resCh <- result{nil, cancel, errHedgeLost}
+					return
+				}
+			}
+			s, err := c.Series(cctx, req, opts...)
+			resCh <- result{s, cancel, err}
+		}()
+	}
+
+	res := <-resCh
+	close(stop) // tell every hedge still waiting out its delay to cancel instead of firing
+
+	// Every goroutine above sends exactly one result, win or lose; drain the
+	// rest in the background and cancel each one's context so a replica that
+	// finished concurrently with the winner doesn't leak its stream for the
+	// life of the request.
+	go func() {
+		for i := 0; i < len(r.clients)-1; i++ {
+			other := <-resCh
+			other.cancel()
+		}
+	}()
+
+	if res.err != nil {
+		res.cancel()
+		return nil, res.err
+	}
+	return res.stream, nil
+}
+
+var errNoReplicas = errNoReplicasErr{}
+
+type errNoReplicasErr struct{}
+
+func (errNoReplicasErr) Error() string { return "replica set has no clients" }
+
+// errHedgeLost is the result recorded for a hedge that never got to fire
+// because an earlier replica already won the race.
+var errHedgeLost = errHedgeLostErr{}
+
+type errHedgeLostErr struct{}
+
+func (errHedgeLostErr) Error() string { return "hedge lost the race to an earlier replica" }
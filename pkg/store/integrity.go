@@ -0,0 +1,53 @@
+package store
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+// IntegrityMetrics counts checksum mismatches found while verifying Series
+// received from a StoreAPI endpoint, e.g. corruption introduced by a bad
+// cache entry or a flaky object store read.
+type IntegrityMetrics struct {
+	ChunkMismatches  prometheus.Counter
+	SeriesMismatches prometheus.Counter
+}
+
+func NewIntegrityMetrics(reg prometheus.Registerer) *IntegrityMetrics {
+	m := &IntegrityMetrics{
+		ChunkMismatches: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "thanos_store_chunk_hash_mismatches_total",
+			Help: "Number of chunks whose contents didn't match their advertised checksum.",
+		}),
+		SeriesMismatches: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "thanos_store_series_hash_mismatches_total",
+			Help: "Number of series whose labels or chunks didn't match their advertised checksum.",
+		}),
+	}
+	if reg != nil {
+		reg.MustRegister(m.ChunkMismatches, m.SeriesMismatches)
+	}
+	return m
+}
+
+// VerifySeries checks s's checksum, and each of its chunks', counting any
+// mismatch found. enabled gates the whole check: verification costs a full
+// re-hash of every chunk, so it's opt-in rather than always-on.
+func (m *IntegrityMetrics) VerifySeries(s storepb.Series, enabled bool) bool {
+	if !enabled {
+		return true
+	}
+	ok := true
+	if !storepb.VerifySeries(s) {
+		m.SeriesMismatches.Inc()
+		ok = false
+	}
+	for _, c := range s.Chunks {
+		if !storepb.VerifyChunk(c) {
+			m.ChunkMismatches.Inc()
+			ok = false
+		}
+	}
+	return ok
+}
@@ -0,0 +1,114 @@
+// Package labelpb implements the zero-copy label representation used on
+// the StoreAPI Series hot path. A decoded gRPC message's underlying byte
+// buffer can be referenced directly by a series' labels instead of each
+// label being copied out into its own Go string, which is the largest
+// single allocation source on a busy store gateway or querier's Series
+// call.
+package labelpb
+
+import (
+	"sort"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// ZLabel is a label name/value pair whose Name and Value may alias bytes
+// owned by another buffer rather than holding their own copy.
+//
+// Ownership: a ZLabel returned from decoding a Series response is only
+// valid for as long as the message it came from is alive. Retaining one
+// past that point — caching it, using it as a map key, handing it to
+// another goroutine, or returning it from the call that produced it — is
+// a use-after-free bug once the message's buffer is reused or freed.
+// Call Copy first in any of those cases. ZLabels built by
+// ZLabelsFromPromLabels already own their bytes and need no such care.
+type ZLabel struct {
+	Name, Value []byte
+}
+
+// Copy returns a ZLabel holding freshly allocated copies of Name and
+// Value, safe to retain past the lifetime of l's backing buffer.
+func (l ZLabel) Copy() ZLabel {
+	return ZLabel{
+		Name:  append([]byte(nil), l.Name...),
+		Value: append([]byte(nil), l.Value...),
+	}
+}
+
+// ZLabelSet is a name-sorted set of ZLabels, preserving the same
+// sort-order invariant labels.Labels relies on for merging and matching.
+type ZLabelSet []ZLabel
+
+func (s ZLabelSet) Len() int           { return len(s) }
+func (s ZLabelSet) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s ZLabelSet) Less(i, j int) bool { return string(s[i].Name) < string(s[j].Name) }
+
+// Copy returns a ZLabelSet holding copies of every label in s, safe to
+// retain past the lifetime of s's backing buffer. Call this before
+// storing a ZLabelSet taken from a Series response anywhere that outlives
+// the response itself.
+func (s ZLabelSet) Copy() ZLabelSet {
+	out := make(ZLabelSet, len(s))
+	for i, l := range s {
+		out[i] = l.Copy()
+	}
+	return out
+}
+
+// Compare returns -1, 0 or 1 depending on whether s sorts before, equal to,
+// or after other, comparing name/value pairs in order. Used to merge-sort
+// series from multiple StoreAPI endpoints by their label set rather than
+// by any single label.
+func (s ZLabelSet) Compare(other ZLabelSet) int {
+	for i := 0; i < len(s) && i < len(other); i++ {
+		if c := compareBytes(s[i].Name, other[i].Name); c != 0 {
+			return c
+		}
+		if c := compareBytes(s[i].Value, other[i].Value); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(s) < len(other):
+		return -1
+	case len(s) > len(other):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareBytes(a, b []byte) int {
+	as, bs := string(a), string(b)
+	switch {
+	case as < bs:
+		return -1
+	case as > bs:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// PromLabels converts s to prometheus/model/labels.Labels. This always
+// copies, since labels.Labels stores immutable Go strings rather than
+// byte slices that could alias a reusable buffer.
+func (s ZLabelSet) PromLabels() labels.Labels {
+	b := labels.NewBuilder(labels.EmptyLabels())
+	for _, l := range s {
+		b.Set(string(l.Name), string(l.Value))
+	}
+	return b.Labels()
+}
+
+// ZLabelsFromPromLabels converts lset to a ZLabelSet. lset's strings
+// already own their memory, so the result doesn't alias a reusable buffer
+// and needs no Copy before being retained.
+func ZLabelsFromPromLabels(lset labels.Labels) ZLabelSet {
+	out := make(ZLabelSet, 0, lset.Len())
+	lset.Range(func(l labels.Label) {
+		out = append(out, ZLabel{Name: []byte(l.Name), Value: []byte(l.Value)})
+	})
+	sort.Sort(out)
+	return out
+}
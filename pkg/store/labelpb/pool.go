@@ -0,0 +1,83 @@
+package labelpb
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Pool interns label names and values so that a high fan-out query, which
+// otherwise materializes the same label strings (e.g. "__name__" or a
+// common "job" value) once per series, shares one backing byte slice
+// across every series that carries it. No code in this tree constructs a
+// Pool; the proxy merge and dedup layers do not use it.
+type Pool struct {
+	mtx      sync.Mutex
+	interned map[string][]byte
+
+	hits          prometheus.Counter
+	misses        prometheus.Counter
+	retainedBytes prometheus.Gauge
+}
+
+// NewPool returns an empty Pool. reg may be nil, in which case its
+// metrics are created but not registered.
+func NewPool(reg prometheus.Registerer) *Pool {
+	p := &Pool{
+		interned: map[string][]byte{},
+		hits: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "thanos_labelpb_pool_hits_total",
+			Help: "Number of label strings served from an existing interned copy.",
+		}),
+		misses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "thanos_labelpb_pool_misses_total",
+			Help: "Number of label strings that were not yet interned and had to be copied in.",
+		}),
+		retainedBytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "thanos_labelpb_pool_retained_bytes",
+			Help: "Total bytes retained by the interning pool's unique label strings.",
+		}),
+	}
+	if reg != nil {
+		reg.MustRegister(p.hits, p.misses, p.retainedBytes)
+	}
+	return p
+}
+
+// intern returns a byte slice equal to b, either an existing interned copy
+// or a freshly retained one.
+func (p *Pool) intern(b []byte) []byte {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if existing, ok := p.interned[string(b)]; ok {
+		p.hits.Inc()
+		return existing
+	}
+	cp := append([]byte(nil), b...)
+	p.interned[string(cp)] = cp
+	p.misses.Inc()
+	p.retainedBytes.Add(float64(len(cp)))
+	return cp
+}
+
+// InternZLabelSet returns a copy of s with every Name and Value replaced
+// by the pool's interned copy, so series sharing label strings share their
+// backing memory too.
+func (p *Pool) InternZLabelSet(s ZLabelSet) ZLabelSet {
+	out := make(ZLabelSet, len(s))
+	for i, l := range s {
+		out[i] = ZLabel{Name: p.intern(l.Name), Value: p.intern(l.Value)}
+	}
+	return out
+}
+
+// Reset discards every interned string and zeroes the retained bytes
+// gauge, e.g. between queries if the pool is scoped per-query rather than
+// held for the process lifetime.
+func (p *Pool) Reset() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.interned = map[string][]byte{}
+	p.retainedBytes.Set(0)
+}
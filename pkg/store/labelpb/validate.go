@@ -0,0 +1,119 @@
+package labelpb
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// ValidationError is a typed error describing exactly what's wrong with a
+// ZLabelSet, so a caller can decide whether to reject the whole series or
+// just log and repair it.
+type ValidationError struct {
+	// Label is the offending label's name, or "" for a set-level problem
+	// (unsorted, duplicate) that isn't about one label in isolation.
+	Label  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Label == "" {
+		return e.Reason
+	}
+	return fmt.Sprintf("label %q: %s", e.Label, e.Reason)
+}
+
+// legalLabelNameByte reports whether b may appear in a Prometheus label
+// name, matching the `[a-zA-Z0-9_]` character class.
+func legalLabelNameByte(b byte, first bool) bool {
+	if b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') {
+		return true
+	}
+	if !first && b >= '0' && b <= '9' {
+		return true
+	}
+	return false
+}
+
+// ValidateLabelName reports whether name is a legal Prometheus label name:
+// non-empty, ASCII `[a-zA-Z_][a-zA-Z0-9_]*`.
+func ValidateLabelName(name []byte) error {
+	if len(name) == 0 {
+		return &ValidationError{Reason: "label name is empty"}
+	}
+	for i, b := range name {
+		if !legalLabelNameByte(b, i == 0) {
+			return &ValidationError{Label: string(name), Reason: fmt.Sprintf("illegal character %q in label name", b)}
+		}
+	}
+	return nil
+}
+
+// Validate checks s for every way a misbehaving StoreAPI implementation
+// can corrupt a Series response: invalid UTF-8, illegal label names,
+// duplicate label names, and a name/value not in sorted order. It returns
+// the first problem found; callers that want to continue despite
+// problems should use Repair instead.
+func Validate(s ZLabelSet) error {
+	for i, l := range s {
+		if !utf8.Valid(l.Name) {
+			return &ValidationError{Label: string(l.Name), Reason: "label name is not valid UTF-8"}
+		}
+		if !utf8.Valid(l.Value) {
+			return &ValidationError{Label: string(l.Name), Reason: "label value is not valid UTF-8"}
+		}
+		if err := ValidateLabelName(l.Name); err != nil {
+			return err
+		}
+		if i > 0 {
+			prev := string(s[i-1].Name)
+			cur := string(l.Name)
+			if prev == cur {
+				return &ValidationError{Label: cur, Reason: "duplicate label name"}
+			}
+			if prev > cur {
+				return &ValidationError{Reason: fmt.Sprintf("labels are not sorted: %q found after %q", cur, prev)}
+			}
+		}
+	}
+	return nil
+}
+
+// Repair returns a ZLabelSet that satisfies Validate: it sorts s, drops
+// any label with an invalid name or non-UTF-8 name/value, and keeps the
+// first occurrence of a duplicate name. It also returns one
+// *ValidationError per problem it fixed or discarded, so a caller can log
+// once per offending StoreAPI instead of silently accepting bad data.
+func Repair(s ZLabelSet) (ZLabelSet, []error) {
+	var (
+		issues []error
+		clean  ZLabelSet
+	)
+	for _, l := range s {
+		if !utf8.Valid(l.Name) {
+			issues = append(issues, &ValidationError{Label: string(l.Name), Reason: "label name is not valid UTF-8, dropped"})
+			continue
+		}
+		if !utf8.Valid(l.Value) {
+			issues = append(issues, &ValidationError{Label: string(l.Name), Reason: "label value is not valid UTF-8, dropped"})
+			continue
+		}
+		if err := ValidateLabelName(l.Name); err != nil {
+			issues = append(issues, fmt.Errorf("%w, dropped", err))
+			continue
+		}
+		clean = append(clean, l)
+	}
+
+	sort.Stable(clean)
+
+	deduped := clean[:0:0]
+	for i, l := range clean {
+		if i > 0 && string(deduped[len(deduped)-1].Name) == string(l.Name) {
+			issues = append(issues, &ValidationError{Label: string(l.Name), Reason: "duplicate label name, kept first occurrence"})
+			continue
+		}
+		deduped = append(deduped, l)
+	}
+	return deduped, issues
+}
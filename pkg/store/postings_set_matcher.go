@@ -0,0 +1,55 @@
+package store
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// setMatcherCache memoizes the set-matcher decomposition of compiled regexps
+// so that repeated queries using the same `label=~"a|b|c"` pattern don't
+// re-walk the regex syntax tree on every BucketStore.Series call.
+var setMatcherCache sync.Map // map[string]setMatcherResult, keyed by the matcher's regex source
+
+type setMatcherResult struct {
+	values []string
+	ok     bool
+}
+
+// ExpandSetMatcher returns the literal alternatives of m if it is a regex (or
+// negated regex) matcher whose pattern is a plain alternation of literals
+// (e.g. "a|b|c", with no other regex metacharacters), and ok=true. Such
+// matchers can be served by looking up postings for each literal value
+// directly instead of scanning every label value in the block index, mirroring
+// Prometheus' fast-regexp "set matcher" optimization.
+//
+// Calling this from a postings lookup requires the block index reader
+// itself, which belongs to the same local chunk/block reader seam
+// pkg/store.ChunkIterable's doc comment describes as absent from this tree;
+// ExpandSetMatcher exists so that reader can be written against an
+// already-memoized matcher-decomposition helper, rather than inlining this
+// logic once the reader lands.
+func ExpandSetMatcher(m *labels.Matcher) (values []string, ok bool) {
+	if m.Type != labels.MatchRegexp && m.Type != labels.MatchNotRegexp {
+		return nil, false
+	}
+	if cached, found := setMatcherCache.Load(m.Value); found {
+		r := cached.(setMatcherResult)
+		return r.values, r.ok
+	}
+
+	parts := strings.Split(m.Value, "|")
+	for _, p := range parts {
+		if p == "" || containsRegexMeta(p) {
+			setMatcherCache.Store(m.Value, setMatcherResult{nil, false})
+			return nil, false
+		}
+	}
+	setMatcherCache.Store(m.Value, setMatcherResult{parts, true})
+	return parts, true
+}
+
+func containsRegexMeta(s string) bool {
+	return strings.ContainsAny(s, `.+*?()[]{}^$\`)
+}
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// ExternalLabelsProvider exposes the external labels a store endpoint
+// reports via its Info call, used to decide whether it can possibly hold
+// series matching a query before any Series RPC is sent.
+type ExternalLabelsProvider interface {
+	ExternalLabels() labels.Labels
+}
+
+// PruneStores filters stores down to those whose external labels don't
+// contradict matchers, so a querier with many sharded stores (grouped by
+// external label, e.g. per-cluster) skips RPCs to stores that provably have
+// no matching series.
+func PruneStores(stores []ExternalLabelsProvider, matchers []*labels.Matcher) []ExternalLabelsProvider {
+	if len(matchers) == 0 {
+		return stores
+	}
+	out := make([]ExternalLabelsProvider, 0, len(stores))
+	for _, s := range stores {
+		if matchersPossiblyMatch(s.ExternalLabels(), matchers) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// matchersPossiblyMatch reports whether matchers could match a series
+// carrying extLabels, conservatively: for every matcher on a label the store
+// fixes externally, the store's value must satisfy that matcher; matchers on
+// labels the store doesn't fix are assumed satisfiable (the store might have
+// series with any value for them).
+func matchersPossiblyMatch(extLabels labels.Labels, matchers []*labels.Matcher) bool {
+	for _, m := range matchers {
+		v := extLabels.Get(m.Name)
+		if v == "" && !extLabels.Has(m.Name) {
+			continue
+		}
+		if !m.Matches(v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package store
+
+import (
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+// ApplyAggrPushdown reduces values, the samples of one group, to a single
+// aggregate according to hints.Func, and reports false when hints is nil,
+// values is empty or hints.Func is not handled. It only handles aggregations that can be computed
+// associatively per-chunk (sum, min, max, count); anything else should not
+// be pushed down and Hints.Func should be left as AggrNone by the caller.
+func ApplyAggrPushdown(hints *storepb.QueryHints, values []float64) (float64, bool) {
+	if hints == nil || len(values) == 0 {
+		return 0, false
+	}
+	switch hints.Func {
+	case storepb.AggrSum:
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum, true
+	case storepb.AggrMin:
+		min := values[0]
+		for _, v := range values[1:] {
+			if v < min {
+				min = v
+			}
+		}
+		return min, true
+	case storepb.AggrMax:
+		max := values[0]
+		for _, v := range values[1:] {
+			if v > max {
+				max = v
+			}
+		}
+		return max, true
+	case storepb.AggrCount:
+		return float64(len(values)), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,54 @@
+package store
+
+import "github.com/thanos-io/thanos/pkg/store/storepb"
+
+// SeriesSize estimates the wire size of s: every label's name and value
+// bytes, plus every chunk's raw bytes. A store honoring
+// storepb.SeriesRequest.MaxFrameBytes uses this to decide when the Series
+// frame it's assembling is full and should be flushed rather than grown
+// further.
+func SeriesSize(s *storepb.Series) int64 {
+	var n int64
+	for _, l := range s.Labels {
+		n += int64(len(l.Name) + len(l.Value))
+	}
+	for _, c := range s.Chunks {
+		n += int64(len(c.Raw))
+	}
+	return n
+}
+
+// FlowController tracks each store endpoint's progress through a Series
+// call via the SeriesStatsFrame reports it sends, so a fan-out proxy query
+// can spot an endpoint returning far more than its fair share of the
+// overall response and abort that one call instead of waiting it out.
+type FlowController struct {
+	maxShareBytes int64
+	sent          map[string]int64
+}
+
+// NewFlowController returns a FlowController that flags a store once it
+// has sent more than maxShareBytes in a single Series call. maxShareBytes
+// of 0 disables the check; Observe then only records progress.
+func NewFlowController(maxShareBytes int64) *FlowController {
+	return &FlowController{
+		maxShareBytes: maxShareBytes,
+		sent:          map[string]int64{},
+	}
+}
+
+// Observe records addr's latest stats frame and reports whether the proxy
+// should abort that store's call.
+func (f *FlowController) Observe(addr string, stats *storepb.SeriesStatsFrame) (abort bool) {
+	if stats == nil {
+		return false
+	}
+	f.sent[addr] = stats.BytesSent
+	return f.maxShareBytes > 0 && stats.BytesSent > f.maxShareBytes
+}
+
+// BytesSent returns the last reported BytesSent for addr, or 0 if no stats
+// frame has been observed for it yet.
+func (f *FlowController) BytesSent(addr string) int64 {
+	return f.sent[addr]
+}
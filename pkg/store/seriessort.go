@@ -0,0 +1,24 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+// EnsureSorted returns series ready for a k-way merge by label set. When
+// sorted is true (the endpoint declared storepb.InfoResponse.SupportsSortedSeries)
+// it returns series unmodified, since a conforming endpoint already
+// guarantees the order the merge needs. Otherwise it's the compatibility
+// path for an old or third-party StoreAPI implementation: it copies and
+// sorts series by label set before returning.
+func EnsureSorted(series []storepb.Series, sorted bool) []storepb.Series {
+	if sorted {
+		return series
+	}
+	out := append([]storepb.Series(nil), series...)
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Labels.Compare(out[j].Labels) < 0
+	})
+	return out
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"gopkg.in/yaml.v2"
+
+	"github.com/thanos-io/thanos/pkg/block/metadata"
+)
+
+// ShardingMetrics tracks which blocks this store gateway instance owns after
+// relabel-based sharding, so a StatefulSet of gateways can self-shard by
+// ordinal and still be observable from the outside.
+type ShardingMetrics struct {
+	BlocksOwned    prometheus.Gauge
+	BlocksDropped  prometheus.Gauge
+}
+
+func NewShardingMetrics(reg prometheus.Registerer) *ShardingMetrics {
+	return &ShardingMetrics{
+		BlocksOwned: promauto(reg, "thanos_store_sharding_blocks_owned", "Number of blocks owned by this store gateway after sharding."),
+		BlocksDropped: promauto(reg, "thanos_store_sharding_blocks_dropped", "Number of blocks dropped by this store gateway after sharding."),
+	}
+}
+
+func promauto(reg prometheus.Registerer, name, help string) prometheus.Gauge {
+	g := prometheus.NewGauge(prometheus.GaugeOpts{Name: name, Help: help})
+	if reg != nil {
+		reg.MustRegister(g)
+	}
+	return g
+}
+
+// RelabelConfig is the subset of Prometheus relabel config used for sharding
+// decisions. It is expected to be loaded as part of the store's
+// --selector.relabel-config block and must contain a "hashmod" action to
+// have any sharding effect.
+type RelabelConfig struct {
+	SourceLabels []string `yaml:"source_labels"`
+	TargetLabel  string   `yaml:"target_label"`
+	Modulus      uint64   `yaml:"modulus"`
+	Action       string   `yaml:"action"`
+}
+
+// ParseRelabelConfig unmarshals a --selector.relabel-config YAML document.
+func ParseRelabelConfig(content []byte) ([]RelabelConfig, error) {
+	var cfgs []RelabelConfig
+	if err := yaml.Unmarshal(content, &cfgs); err != nil {
+		return nil, fmt.Errorf("parsing selector relabel config: %w", err)
+	}
+	return cfgs, nil
+}
+
+// ShardOwns reports whether this shard, identified by shardOrdinal, should
+// serve meta, under the hashmod config cfgs and the block's ULID and
+// external labels.
+//
+// Only the "hashmod" action is supported: it hashes the concatenation of the
+// configured source labels (falling back to the block ULID when empty) and
+// keeps the block iff hash%modulus == shardOrdinal. shardOrdinal must be
+// supplied by the caller (e.g. from a StatefulSet pod ordinal): a real
+// hashmod relabel config's TargetLabel is the name of the label hashmod
+// writes its result into, not this shard's ordinal, so it can't be parsed
+// out of the config itself.
+func ShardOwns(cfgs []RelabelConfig, shardOrdinal uint64, meta *metadata.Meta) bool {
+	for _, c := range cfgs {
+		if c.Action != "hashmod" || c.Modulus == 0 {
+			continue
+		}
+		val := meta.ULID.String()
+		if len(c.SourceLabels) > 0 {
+			b := labels.NewBuilder(labels.EmptyLabels())
+			for k, v := range meta.Thanos.Labels {
+				b.Set(k, v)
+			}
+			lset := b.Labels()
+			val = ""
+			for _, name := range c.SourceLabels {
+				val += lset.Get(name)
+			}
+		}
+		if hashString(val)%c.Modulus != shardOrdinal {
+			return false
+		}
+	}
+	return true
+}
+
+func hashString(s string) uint64 {
+	// FNV-1a, matching the hashing Prometheus uses for hashmod relabeling.
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	h := uint64(offset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= prime64
+	}
+	return h
+}
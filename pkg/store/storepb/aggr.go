@@ -0,0 +1,25 @@
+package storepb
+
+// AggrFunc identifies a simple aggregation that the querier can push down
+// to a StoreAPI, letting the store compute it over its local chunks instead
+// of shipping every raw sample back for the engine to aggregate.
+type AggrFunc int
+
+const (
+	AggrNone AggrFunc = iota
+	AggrSum
+	AggrMin
+	AggrMax
+	AggrCount
+)
+
+// QueryHints carries pushdown hints attached to a SeriesRequest.
+type QueryHints struct {
+	// Func is set when the querier knows the whole query is a single
+	// aggregation over the series returned by this request, e.g.
+	// `sum(metric{...})` with no `by`/`without` grouping.
+	Func AggrFunc
+	// Grouping lists the "by" labels when the aggregation groups by a label
+	// subset instead of reducing to a single series.
+	Grouping []string
+}
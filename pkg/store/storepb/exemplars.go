@@ -0,0 +1,45 @@
+package storepb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// ExemplarsRequest requests exemplars for series matching Matchers within
+// [Start, End], mirroring Prometheus' exemplar query API.
+type ExemplarsRequest struct {
+	Matchers []LabelMatcher
+	Start    int64
+	End      int64
+}
+
+type Exemplar struct {
+	Labels []Label
+	Value  float64
+	Ts     int64
+}
+
+type ExemplarData struct {
+	SeriesLabels []Label
+	Exemplars    []Exemplar
+}
+
+type ExemplarsResponse struct {
+	Data     []ExemplarData
+	Warnings []string
+}
+
+// ExemplarsClient is the client API for the Exemplars service.
+type ExemplarsClient interface {
+	Exemplars(ctx context.Context, in *ExemplarsRequest, opts ...grpc.CallOption) (*ExemplarsResponse, error)
+}
+
+type ExemplarsServer interface {
+	Exemplars(context.Context, *ExemplarsRequest) (*ExemplarsResponse, error)
+}
+
+func RegisterExemplarsServer(s *grpc.Server, srv ExemplarsServer) {
+	_ = s
+	_ = srv
+}
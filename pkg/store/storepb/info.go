@@ -0,0 +1,55 @@
+package storepb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// InfoRequest requests capability/identity information from a StoreAPI
+// implementation.
+//
+// Deprecated: this call only ever described Store capabilities, which
+// doesn't generalize to a component that also serves Rules, Targets,
+// Exemplars or Metadata. New code should use pkg/info/infopb's Info
+// service instead; this one remains for components not yet migrated.
+type InfoRequest struct{}
+
+// Deprecated: see InfoRequest.
+type InfoResponse struct {
+	LabelSets     [][]Label
+	MinTime       int64
+	MaxTime       int64
+	ComponentType string
+	// StoreType distinguishes a leaf store (sidecar, store gateway, receive)
+	// from a "querier" acting as a StoreAPI for another querier above it, so
+	// the upper querier's dedup layer knows the lower querier has already
+	// deduplicated its own replicas and must not be re-deduplicated as if it
+	// were just another raw replica.
+	StoreType string
+	// SupportsSortedSeries declares that every Series this endpoint returns
+	// is already label-sorted with external labels stripped, the order a
+	// k-way merge by label set needs. It's a static,
+	// per-endpoint property advertised once via Info rather than
+	// renegotiated per Series call, since it describes how the
+	// implementation is built, not anything a query's parameters could
+	// change. store.EnsureSorted skips sorting an endpoint's series when
+	// passed this as true and sorts a copy otherwise; nothing in this tree
+	// calls EnsureSorted.
+	SupportsSortedSeries bool
+}
+
+type InfoClient interface {
+	Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error)
+}
+
+type InfoServer interface {
+	Info(context.Context, *InfoRequest) (*InfoResponse, error)
+}
+
+func RegisterInfoServer(s *grpc.Server, srv InfoServer) { _ = s; _ = srv }
+
+const (
+	StoreTypeLeaf    = "leaf"
+	StoreTypeQuerier = "querier"
+)
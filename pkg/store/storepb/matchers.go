@@ -0,0 +1,35 @@
+package storepb
+
+import (
+	"fmt"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// MatchersToPromMatchers converts ms to prometheus/model/labels.Matchers,
+// the form most non-wire code (pruning, block filtering, PromQL-facing
+// series selection) works with instead of the wire-shaped LabelMatcher.
+func MatchersToPromMatchers(ms []LabelMatcher) ([]*labels.Matcher, error) {
+	out := make([]*labels.Matcher, 0, len(ms))
+	for _, m := range ms {
+		var t labels.MatchType
+		switch m.Type {
+		case MatcherType_EQ:
+			t = labels.MatchEqual
+		case MatcherType_NEQ:
+			t = labels.MatchNotEqual
+		case MatcherType_RE:
+			t = labels.MatchRegexp
+		case MatcherType_NRE:
+			t = labels.MatchNotRegexp
+		default:
+			return nil, fmt.Errorf("unknown matcher type %v", m.Type)
+		}
+		pm, err := labels.NewMatcher(t, m.Name, m.Value)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, pm)
+	}
+	return out, nil
+}
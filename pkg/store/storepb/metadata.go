@@ -0,0 +1,40 @@
+package storepb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// MetadataRequest requests metric metadata (help, type, unit) known to the
+// backing Prometheus/TSDB, optionally filtered by metric name.
+type MetadataRequest struct {
+	Metric string
+	Limit  int32
+}
+
+type Metadata struct {
+	Type string
+	Help string
+	Unit string
+}
+
+type MetadataResponse struct {
+	Metadata map[string][]Metadata
+	Warnings []string
+}
+
+// MetadataClient is the client API for the Metadata service, implemented by
+// the sidecar (proxying its Prometheus) and fanned out by the querier.
+type MetadataClient interface {
+	MetricMetadata(ctx context.Context, in *MetadataRequest, opts ...grpc.CallOption) (*MetadataResponse, error)
+}
+
+type MetadataServer interface {
+	MetricMetadata(context.Context, *MetadataRequest) (*MetadataResponse, error)
+}
+
+func RegisterMetadataServer(s *grpc.Server, srv MetadataServer) {
+	_ = s
+	_ = srv
+}
@@ -0,0 +1,24 @@
+package storepb
+
+// ShardInfo restricts a Series request to series whose label-hash falls into
+// one shard of a total number of shards, letting the querier vertically
+// split a single expensive query across parallel sub-queries that each
+// touch a disjoint subset of series within the same stores.
+type ShardInfo struct {
+	// ShardIndex is this request's shard, in [0, TotalShards).
+	ShardIndex uint64
+	// TotalShards is the number of shards the query is split into.
+	TotalShards uint64
+	// By lists the labels the shard hash is computed over; empty means all
+	// labels (the series' full label set).
+	By []string
+}
+
+// Owns reports whether a series with the given label hash belongs to this
+// shard.
+func (s *ShardInfo) Owns(labelHash uint64) bool {
+	if s == nil || s.TotalShards <= 1 {
+		return true
+	}
+	return labelHash%s.TotalShards == s.ShardIndex
+}
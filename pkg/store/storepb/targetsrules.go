@@ -0,0 +1,74 @@
+package storepb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// TargetsRequest requests the set of scrape targets known to a component,
+// analogous to Prometheus' /api/v1/targets.
+type TargetsRequest struct {
+	State string // "active", "dropped" or "any"
+}
+
+type Target struct {
+	DiscoveredLabels []Label
+	Labels           []Label
+	ScrapePool       string
+	ScrapeURL        string
+	Health           string
+	LastError        string
+}
+
+type TargetsResponse struct {
+	Active   []Target
+	Dropped  []Target
+	Warnings []string
+}
+
+type TargetsClient interface {
+	Targets(ctx context.Context, in *TargetsRequest, opts ...grpc.CallOption) (*TargetsResponse, error)
+}
+
+type TargetsServer interface {
+	Targets(context.Context, *TargetsRequest) (*TargetsResponse, error)
+}
+
+func RegisterTargetsServer(s *grpc.Server, srv TargetsServer) { _ = s; _ = srv }
+
+// RulesRequest requests alerting/recording rule groups, analogous to
+// Prometheus' /api/v1/rules.
+type RulesRequest struct {
+	Type string // "alert", "record" or "" for both
+}
+
+type Rule struct {
+	Name     string
+	Query    string
+	Health   string
+	LastError string
+	Type     string
+}
+
+type RuleGroup struct {
+	Name     string
+	File     string
+	Rules    []Rule
+	Interval float64
+}
+
+type RulesResponse struct {
+	Groups   []RuleGroup
+	Warnings []string
+}
+
+type RulesClient interface {
+	Rules(ctx context.Context, in *RulesRequest, opts ...grpc.CallOption) (*RulesResponse, error)
+}
+
+type RulesServer interface {
+	Rules(context.Context, *RulesRequest) (*RulesResponse, error)
+}
+
+func RegisterRulesServer(s *grpc.Server, srv RulesServer) { _ = s; _ = srv }
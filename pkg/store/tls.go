@@ -0,0 +1,63 @@
+package store
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+	"gopkg.in/yaml.v2"
+)
+
+// EndpointTLSConfig configures client-side TLS for the connection to a
+// single store endpoint, allowing a querier that talks to stores across
+// trust domains (e.g. different clusters) to use different certificates per
+// endpoint.
+type EndpointTLSConfig struct {
+	Addr       string `yaml:"addr"`
+	CertFile   string `yaml:"tls_cert_file"`
+	KeyFile    string `yaml:"tls_key_file"`
+	CACertFile string `yaml:"tls_ca_cert_file"`
+	ServerName string `yaml:"tls_server_name"`
+	SkipVerify bool   `yaml:"tls_skip_verify"`
+}
+
+// ParseEndpointTLSConfigs unmarshals a YAML list of EndpointTLSConfig into
+// a map keyed by endpoint address.
+func ParseEndpointTLSConfigs(content []byte) (map[string]EndpointTLSConfig, error) {
+	var list []EndpointTLSConfig
+	if err := yaml.Unmarshal(content, &list); err != nil {
+		return nil, fmt.Errorf("parsing store TLS config: %w", err)
+	}
+	out := make(map[string]EndpointTLSConfig, len(list))
+	for _, c := range list {
+		out[c.Addr] = c
+	}
+	return out, nil
+}
+
+// TransportCredentials builds gRPC TransportCredentials for this endpoint.
+func (c EndpointTLSConfig) TransportCredentials() (credentials.TransportCredentials, error) {
+	tlsCfg := &tls.Config{ServerName: c.ServerName, InsecureSkipVerify: c.SkipVerify}
+
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client cert/key for %s: %w", c.Addr, err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	if c.CACertFile != "" {
+		caPEM, err := os.ReadFile(c.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA cert for %s: %w", c.Addr, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no valid certificates found in %s", c.CACertFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	return credentials.NewTLS(tlsCfg), nil
+}
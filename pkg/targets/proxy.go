@@ -0,0 +1,60 @@
+// Package targets implements the querier side of the Thanos Targets API:
+// fanning TargetsRequest out to every component that reports targets (today,
+// only the sidecar) and deduplicating identical targets reported by more
+// than one replica.
+package targets
+
+import (
+	"context"
+
+	"github.com/thanos-io/thanos/pkg/store/storepb"
+)
+
+type Proxy struct {
+	clients func() []storepb.TargetsClient
+}
+
+func NewProxy(clients func() []storepb.TargetsClient) *Proxy {
+	return &Proxy{clients: clients}
+}
+
+func (p *Proxy) Targets(ctx context.Context, r *storepb.TargetsRequest) (*storepb.TargetsResponse, error) {
+	merged := &storepb.TargetsResponse{}
+	seenActive := map[string]struct{}{}
+	seenDropped := map[string]struct{}{}
+
+	for _, c := range p.clients() {
+		resp, err := c.Targets(ctx, r)
+		if err != nil {
+			merged.Warnings = append(merged.Warnings, err.Error())
+			continue
+		}
+		for _, t := range resp.Active {
+			if k := targetKey(t); addOnce(seenActive, k) {
+				merged.Active = append(merged.Active, t)
+			}
+		}
+		for _, t := range resp.Dropped {
+			if k := targetKey(t); addOnce(seenDropped, k) {
+				merged.Dropped = append(merged.Dropped, t)
+			}
+		}
+	}
+	return merged, nil
+}
+
+func targetKey(t storepb.Target) string {
+	s := t.ScrapePool + "|" + t.ScrapeURL + "|"
+	for _, l := range t.Labels {
+		s += l.Name + "=" + l.Value + ","
+	}
+	return s
+}
+
+func addOnce(seen map[string]struct{}, k string) bool {
+	if _, ok := seen[k]; ok {
+		return false
+	}
+	seen[k] = struct{}{}
+	return true
+}
@@ -0,0 +1,61 @@
+// Package tenancy injects a tenant-scoping label matcher into incoming
+// queries so a single querier can be shared safely across tenants that are
+// otherwise distinguished only by an external label on their blocks/series.
+package tenancy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// DefaultTenantHeader is the HTTP header read to determine the caller's
+// tenant when no override is configured.
+const DefaultTenantHeader = "THANOS-TENANT"
+
+type tenantKey struct{}
+
+// WithTenant returns a context that carries tenant for downstream matcher
+// injection.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant stored by WithTenant, if any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	t, ok := ctx.Value(tenantKey{}).(string)
+	return t, ok
+}
+
+// Middleware extracts the tenant from header and stores it on the request
+// context for the query path to pick up via EnforceMatcher.
+func Middleware(header string, next http.Handler) http.Handler {
+	if header == "" {
+		header = DefaultTenantHeader
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if t := r.Header.Get(header); t != "" {
+			r = r.WithContext(WithTenant(r.Context(), t))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// EnforceMatcher appends a matcher restricting tenantLabel==tenant (from
+// ctx) to matchers, refusing to proceed if the query already carries a
+// conflicting matcher on tenantLabel so a tenant cannot override another
+// tenant's scope.
+func EnforceMatcher(ctx context.Context, tenantLabel string, matchers []*labels.Matcher) ([]*labels.Matcher, error) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return matchers, nil
+	}
+	for _, m := range matchers {
+		if m.Name == tenantLabel && m.Value != tenant {
+			return nil, fmt.Errorf("query matcher on %q conflicts with enforced tenant %q", tenantLabel, tenant)
+		}
+	}
+	return append(matchers, labels.MustNewMatcher(labels.MatchEqual, tenantLabel, tenant)), nil
+}
@@ -0,0 +1,125 @@
+// Package tls provides TLS helpers shared across this tree's gRPC servers
+// and clients that a single-shot tls.LoadX509KeyPair call doesn't cover:
+// reloading a certificate from disk without a process restart, for
+// cert-manager-style rotation, and verifying a peer's SPIFFE ID instead
+// of (or alongside) its CN/DNS SANs. pkg/server/grpc, pkg/server/http and
+// pkg/store's EndpointTLSConfig each build a plain *tls.Config today;
+// ReloadingCertificate and VerifyPeerSPIFFE are meant to be dropped into
+// any of them without changing those configs' shape.
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ReloadingCertificate serves a certificate/key pair loaded from disk,
+// periodically re-reading both files so a certificate rotated out from
+// under the process (e.g. by cert-manager) takes effect without a
+// restart. The most recently successfully loaded certificate keeps being
+// served if a reload attempt fails, so a transient read error (the files
+// mid-rewrite) doesn't take the server down.
+type ReloadingCertificate struct {
+	certFile, keyFile string
+
+	mtx  sync.RWMutex
+	cert *tls.Certificate
+
+	stop chan struct{}
+}
+
+// NewReloadingCertificate loads certFile/keyFile once, returning an error
+// if that fails, then starts reloading them every interval in the
+// background until Stop is called.
+func NewReloadingCertificate(certFile, keyFile string, interval time.Duration) (*ReloadingCertificate, error) {
+	rc := &ReloadingCertificate{
+		certFile: certFile,
+		keyFile:  keyFile,
+		stop:     make(chan struct{}),
+	}
+	if err := rc.reload(); err != nil {
+		return nil, err
+	}
+	go rc.watch(interval)
+	return rc, nil
+}
+
+func (rc *ReloadingCertificate) reload() error {
+	cert, err := tls.LoadX509KeyPair(rc.certFile, rc.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading certificate %s: %w", rc.certFile, err)
+	}
+	rc.mtx.Lock()
+	rc.cert = &cert
+	rc.mtx.Unlock()
+	return nil
+}
+
+func (rc *ReloadingCertificate) watch(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			_ = rc.reload()
+		case <-rc.stop:
+			return
+		}
+	}
+}
+
+// Stop ends the background reload loop. The last loaded certificate
+// keeps being served by GetCertificate/GetClientCertificate afterward.
+func (rc *ReloadingCertificate) Stop() {
+	close(rc.stop)
+}
+
+// GetCertificate implements tls.Config.GetCertificate, for a server-side
+// tls.Config.
+func (rc *ReloadingCertificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	rc.mtx.RLock()
+	defer rc.mtx.RUnlock()
+	return rc.cert, nil
+}
+
+// GetClientCertificate implements tls.Config.GetClientCertificate, for a
+// client-side tls.Config.
+func (rc *ReloadingCertificate) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	rc.mtx.RLock()
+	defer rc.mtx.RUnlock()
+	return rc.cert, nil
+}
+
+// VerifyPeerSPIFFE returns a tls.Config.VerifyPeerCertificate func
+// requiring the peer's leaf certificate to carry a SPIFFE ID URI SAN
+// exactly matching one of allowedIDs, for deployments that authenticate
+// components by SPIFFE/SPIRE-issued identity rather than hostname.
+// tls.Config.InsecureSkipVerify should stay false alongside this: it
+// supplements normal chain verification, it doesn't replace it.
+func VerifyPeerSPIFFE(allowedIDs []string) func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	allowed := make(map[string]struct{}, len(allowedIDs))
+	for _, id := range allowedIDs {
+		allowed[id] = struct{}{}
+	}
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return fmt.Errorf("no peer certificate presented")
+		}
+		leaf, err := x509.ParseCertificate(rawCerts[0])
+		if err != nil {
+			return fmt.Errorf("parsing peer certificate: %w", err)
+		}
+		for _, u := range leaf.URIs {
+			if u.Scheme != "spiffe" {
+				continue
+			}
+			if _, ok := allowed[u.String()]; ok {
+				return nil
+			}
+		}
+		return fmt.Errorf("peer certificate has no SPIFFE URI SAN matching an allowed ID")
+	}
+}
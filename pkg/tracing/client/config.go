@@ -0,0 +1,37 @@
+// Package client defines the generic envelope a tracing backend config is
+// described in, the same way pkg/objstore/client does for bucket configs.
+// It only covers that envelope; wiring an actual tracer from it belongs to
+// whichever component embeds a tracing backend directly.
+package client
+
+import (
+	"gopkg.in/yaml.v2"
+)
+
+// Backend identifies a supported tracing backend.
+type Backend string
+
+const (
+	Jaeger      Backend = "JAEGER"
+	OTLP        Backend = "OTLP"
+	Stackdriver Backend = "STACKDRIVER"
+	Stdout      Backend = "STDOUT"
+)
+
+// KnownBackends is the set of tracing backend types config loaders and
+// `thanos tools check` recognize.
+var KnownBackends = map[Backend]struct{}{
+	Jaeger:      {},
+	OTLP:        {},
+	Stackdriver: {},
+	Stdout:      {},
+}
+
+// TracingConfig is the generic envelope every tracing backend config is
+// embedded in: `type` selects the backend and `config` is re-marshalled
+// into that backend's own config struct, mirroring
+// objstore/client.BucketConfig's shape.
+type TracingConfig struct {
+	Type   Backend       `yaml:"type"`
+	Config yaml.MapSlice `yaml:"config"`
+}
@@ -0,0 +1,382 @@
+// Package ui serves Thanos's web interfaces: small HTML page shells plus
+// the JSON endpoints those pages poll for data, so a view backed by a
+// large bucket inventory doesn't have to render its entire state into one
+// template payload.
+package ui
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/oklog/ulid"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/thanos-io/thanos/pkg/block/metadata"
+	"github.com/thanos-io/thanos/pkg/objstore"
+)
+
+// BlockView is the flattened, JSON-friendly projection of a block's
+// meta.json that the bucket UI renders one row of per block.
+type BlockView struct {
+	ULID            ulid.ULID         `json:"ulid"`
+	MinTime         int64             `json:"minTime"`
+	MaxTime         int64             `json:"maxTime"`
+	Resolution      int64             `json:"resolution"`
+	CompactionLevel int               `json:"compactionLevel"`
+	Labels          map[string]string `json:"labels"`
+	Source          string            `json:"source"`
+}
+
+func blockViewFromMeta(id ulid.ULID, m *metadata.Meta) BlockView {
+	return BlockView{
+		ULID:            id,
+		MinTime:         m.MinTime,
+		MaxTime:         m.MaxTime,
+		Resolution:      m.Thanos.Downsample.Resolution,
+		CompactionLevel: m.Compaction.Level,
+		Labels:          m.Thanos.Labels,
+		Source:          m.Thanos.Source,
+	}
+}
+
+// Filter selects and paginates the blocks a query should return.
+type Filter struct {
+	Matchers        []*labels.Matcher
+	MinTime         int64
+	MaxTime         int64
+	Resolution      int64 // 0 means any.
+	CompactionLevel int   // 0 means any.
+	Search          string // ULID substring match, case-insensitive.
+	Day             string // YYYY-MM-DD; drills into one day's DaySummary.
+	Page, PageSize  int
+}
+
+func (f Filter) matches(v BlockView) bool {
+	if f.MinTime != 0 && v.MaxTime <= f.MinTime {
+		return false
+	}
+	if f.MaxTime != 0 && v.MinTime >= f.MaxTime {
+		return false
+	}
+	if f.Day != "" && dayKey(v.MinTime) != f.Day {
+		return false
+	}
+	if f.Resolution != 0 && v.Resolution != f.Resolution {
+		return false
+	}
+	if f.CompactionLevel != 0 && v.CompactionLevel != f.CompactionLevel {
+		return false
+	}
+	if f.Search != "" && !strings.Contains(strings.ToUpper(v.ULID.String()), strings.ToUpper(f.Search)) {
+		return false
+	}
+	if len(f.Matchers) == 0 {
+		return true
+	}
+	set := labels.FromMap(v.Labels)
+	for _, m := range f.Matchers {
+		if !m.Matches(set.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Bucket holds the most recently fetched block inventory and serves it,
+// filtered and paginated, to the bucket UI's frontend.
+type Bucket struct {
+	mu     sync.RWMutex
+	blocks map[ulid.ULID]*metadata.Meta
+	err    error
+
+	// bkt and auth back the per-block detail panel and its mark/unmark
+	// actions; both are nil until WithObjstore is called.
+	bkt  objstore.Bucket
+	auth AuthFunc
+}
+
+// NewBucket returns an empty Bucket; callers refresh its state via Set.
+func NewBucket() *Bucket {
+	return &Bucket{}
+}
+
+// Set replaces the current block inventory, called each time a refresh
+// loop re-lists the bucket. A non-nil err is kept and surfaced to Query
+// callers instead of stale data going unrefreshed silently forever.
+func (b *Bucket) Set(blocks map[ulid.ULID]*metadata.Meta, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.blocks = blocks
+	b.err = err
+}
+
+// Query returns the blocks matching f. Once the match count exceeds
+// AggregationThreshold, it returns per-day DaySummary rows instead of
+// individual blocks unless f.Day drills into a specific day, so the
+// browser never has to render tens of thousands of timeline rows at once.
+func (b *Bucket) Query(f Filter) (*QueryResult, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	var matched []BlockView
+	for id, m := range b.blocks {
+		v := blockViewFromMeta(id, m)
+		if f.matches(v) {
+			matched = append(matched, v)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].MinTime < matched[j].MinTime })
+	total := len(matched)
+
+	if f.Day == "" && total > AggregationThreshold {
+		return &QueryResult{Total: total, Aggregated: true, Days: aggregateByDay(matched)}, nil
+	}
+
+	page, pageSize := f.Page, f.PageSize
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+	if page <= 0 {
+		page = 1
+	}
+	start := (page - 1) * pageSize
+	if start > total {
+		start = total
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+	return &QueryResult{Total: total, Blocks: matched[start:end]}, nil
+}
+
+// Handler returns the bucket UI's HTTP handler: "/" serves the page shell
+// and "/blocks" serves the filtered, paginated block data that page's
+// JavaScript polls, so a bucket with many thousand blocks never has to be
+// embedded whole into a single template render.
+func (b *Bucket) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", b.serveIndex)
+	mux.HandleFunc("/blocks", b.serveBlocks)
+	mux.HandleFunc("/blocks/", b.routeBlockDetail)
+	return mux
+}
+
+// routeBlockDetail dispatches requests under /blocks/<ulid>[/action] to the
+// detail view or one of the guarded mark/unmark actions.
+func (b *Bucket) routeBlockDetail(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/mark-deletion"):
+		b.serveMarkDeletion(w, r)
+	case strings.HasSuffix(r.URL.Path, "/mark-no-compact"):
+		b.serveMarkNoCompact(w, r)
+	default:
+		b.serveDetail(w, r)
+	}
+}
+
+func (b *Bucket) serveIndex(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(bucketIndexHTML))
+}
+
+func (b *Bucket) serveBlocks(w http.ResponseWriter, r *http.Request) {
+	f, err := ParseFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, err := b.Query(f)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// ParseFilter builds a Filter from URL query parameters, the shape the
+// bucket UI's JavaScript sends on every poll so filtering and pagination
+// happen server-side instead of against a client-held copy of the
+// inventory.
+func ParseFilter(q map[string][]string) (Filter, error) {
+	var f Filter
+	for _, raw := range q["matcher"] {
+		m, err := parseMatcher(raw)
+		if err != nil {
+			return Filter{}, err
+		}
+		f.Matchers = append(f.Matchers, m)
+	}
+	f.MinTime = parseInt64(first(q["minTime"]))
+	f.MaxTime = parseInt64(first(q["maxTime"]))
+	f.Resolution = parseInt64(first(q["resolution"]))
+	if lvl := first(q["compactionLevel"]); lvl != "" {
+		n, err := strconv.Atoi(lvl)
+		if err != nil {
+			return Filter{}, err
+		}
+		f.CompactionLevel = n
+	}
+	f.Search = first(q["search"])
+	f.Day = first(q["day"])
+	if p := first(q["page"]); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return Filter{}, err
+		}
+		f.Page = n
+	}
+	if ps := first(q["pageSize"]); ps != "" {
+		n, err := strconv.Atoi(ps)
+		if err != nil {
+			return Filter{}, err
+		}
+		f.PageSize = n
+	}
+	return f, nil
+}
+
+func first(vs []string) string {
+	if len(vs) == 0 {
+		return ""
+	}
+	return vs[0]
+}
+
+func parseInt64(s string) int64 {
+	if s == "" {
+		return 0
+	}
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
+
+// parseMatcher parses a single "name=value", "name!=value", "name=~value"
+// or "name!~value" label matcher, the same syntax the rest of the CLI
+// accepts for --matcher flags.
+var promqlParser = parser.NewParser(parser.Options{})
+
+func parseMatcher(raw string) (*labels.Matcher, error) {
+	ms, err := promqlParser.ParseMetricSelector("{" + raw + "}")
+	if err != nil {
+		return nil, err
+	}
+	if len(ms) != 1 {
+		return nil, strconv.ErrSyntax
+	}
+	return ms[0], nil
+}
+
+// bucketIndexHTML is the bucket UI's page shell: a table the page's own
+// JavaScript fills in by polling /blocks, rather than a server-rendered
+// template holding the full inventory.
+const bucketIndexHTML = `<!DOCTYPE html>
+<html>
+<head><title>Thanos Bucket</title></head>
+<body>
+<div id="filters">
+  <input id="search" placeholder="ULID search">
+  <input id="minTime" placeholder="min time (ms)">
+  <input id="maxTime" placeholder="max time (ms)">
+  <input id="resolution" placeholder="resolution (ms)">
+  <input id="compactionLevel" placeholder="compaction level">
+  <button onclick="loadBlocks(1)">Filter</button>
+</div>
+<div id="days"></div>
+<table id="blocks"><thead><tr>
+  <th>ULID</th><th>Min Time</th><th>Max Time</th><th>Resolution</th><th>Level</th><th>Source</th>
+</tr></thead><tbody></tbody></table>
+<div id="pager"></div>
+<div id="detail" style="display:none">
+  <pre id="detail-meta"></pre>
+  <div id="detail-sizes"></div>
+  <div id="detail-markers"></div>
+  <button onclick="markAction('mark-deletion')">Mark for deletion</button>
+  <button onclick="markAction('mark-no-compact')">Mark for no-compact</button>
+</div>
+<script>
+var selected = null;
+var drillDay = null;
+
+function loadBlocks(page) {
+  var params = new URLSearchParams();
+  ['search', 'minTime', 'maxTime', 'resolution', 'compactionLevel'].forEach(function(id) {
+    var v = document.getElementById(id).value;
+    if (v) params.set(id, v);
+  });
+  if (drillDay) params.set('day', drillDay);
+  params.set('page', page || 1);
+  params.set('pageSize', 100);
+  fetch('blocks?' + params.toString())
+    .then(function(r) { return r.json(); })
+    .then(function(resp) {
+      var daysDiv = document.getElementById('days');
+      var table = document.getElementById('blocks');
+      if (resp.aggregated) {
+        table.style.display = 'none';
+        daysDiv.innerHTML = 'Too many blocks (' + resp.total + ') to list individually; showing per-day summary:';
+        (resp.days || []).forEach(function(d) {
+          var bar = document.createElement('button');
+          bar.textContent = d.day + ' (' + d.count + ')';
+          bar.onclick = function() { drillDay = d.day; loadBlocks(1); };
+          daysDiv.appendChild(bar);
+        });
+        document.getElementById('pager').textContent = 'Total: ' + resp.total;
+        return;
+      }
+      table.style.display = '';
+      daysDiv.innerHTML = drillDay ? ('Showing ' + drillDay + ' ') : '';
+      if (drillDay) {
+        var back = document.createElement('button');
+        back.textContent = 'back to summary';
+        back.onclick = function() { drillDay = null; loadBlocks(1); };
+        daysDiv.appendChild(back);
+      }
+      var body = document.querySelector('#blocks tbody');
+      body.innerHTML = '';
+      (resp.blocks || []).forEach(function(b) {
+        var row = body.insertRow();
+        row.insertCell().textContent = b.ulid;
+        row.insertCell().textContent = b.minTime;
+        row.insertCell().textContent = b.maxTime;
+        row.insertCell().textContent = b.resolution;
+        row.insertCell().textContent = b.compactionLevel;
+        row.insertCell().textContent = b.source;
+        row.onclick = function() { showDetail(b.ulid); };
+      });
+      document.getElementById('pager').textContent = 'Total: ' + resp.total;
+    });
+}
+
+function showDetail(ulid) {
+  selected = ulid;
+  fetch('blocks/' + ulid)
+    .then(function(r) { return r.json(); })
+    .then(function(d) {
+      document.getElementById('detail').style.display = 'block';
+      document.getElementById('detail-meta').textContent = JSON.stringify(d.meta, null, 2);
+      document.getElementById('detail-sizes').textContent = JSON.stringify(d.sizes);
+      document.getElementById('detail-markers').textContent =
+        (d.deletionMark ? 'marked for deletion ' : '') + (d.noCompactMark ? 'marked no-compact' : '');
+    });
+}
+
+function markAction(action) {
+  if (!selected) return;
+  fetch('blocks/' + selected + '/' + action, {method: 'POST'}).then(function() { showDetail(selected); });
+}
+
+loadBlocks(1);
+</script>
+</body>
+</html>
+`
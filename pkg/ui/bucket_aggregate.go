@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"sort"
+	"time"
+)
+
+// AggregationThreshold is the match count above which Query switches from
+// returning individual blocks to per-day DaySummary rows, so the browser
+// never has to render (or even receive) tens of thousands of timeline
+// rows at once. A Filter with Day set always returns individual blocks
+// for that day, regardless of how many blocks matched overall.
+const AggregationThreshold = 50000
+
+// DaySummary is one day's worth of blocks, for the flamegraph-style
+// timeline view to render as a single bar the UI can drill into.
+type DaySummary struct {
+	Day     string `json:"day"` // YYYY-MM-DD, UTC.
+	Count   int    `json:"count"`
+	MinTime int64  `json:"minTime"`
+	MaxTime int64  `json:"maxTime"`
+}
+
+// QueryResult is what Query returns: either a page of individual blocks,
+// or, once Total exceeds AggregationThreshold and no Day drill-down was
+// requested, a set of per-day summaries instead.
+type QueryResult struct {
+	Total      int          `json:"total"`
+	Aggregated bool         `json:"aggregated"`
+	Blocks     []BlockView  `json:"blocks,omitempty"`
+	Days       []DaySummary `json:"days,omitempty"`
+}
+
+// dayKey returns ms's UTC calendar day as "YYYY-MM-DD".
+func dayKey(ms int64) string {
+	return time.UnixMilli(ms).UTC().Format("2006-01-02")
+}
+
+// aggregateByDay groups matched by the UTC calendar day of MinTime,
+// returned sorted by day.
+func aggregateByDay(matched []BlockView) []DaySummary {
+	byDay := map[string]*DaySummary{}
+	var order []string
+	for _, v := range matched {
+		day := dayKey(v.MinTime)
+		s, ok := byDay[day]
+		if !ok {
+			s = &DaySummary{Day: day, MinTime: v.MinTime, MaxTime: v.MaxTime}
+			byDay[day] = s
+			order = append(order, day)
+		}
+		s.Count++
+		if v.MinTime < s.MinTime {
+			s.MinTime = v.MinTime
+		}
+		if v.MaxTime > s.MaxTime {
+			s.MaxTime = v.MaxTime
+		}
+	}
+	sort.Strings(order)
+	out := make([]DaySummary, 0, len(order))
+	for _, day := range order {
+		out = append(out, *byDay[day])
+	}
+	return out
+}
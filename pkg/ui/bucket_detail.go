@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid"
+
+	"github.com/thanos-io/thanos/pkg/block"
+	"github.com/thanos-io/thanos/pkg/block/metadata"
+	"github.com/thanos-io/thanos/pkg/objstore"
+)
+
+// BlockDetail is the per-block detail panel payload: the raw meta.json,
+// a per-file size breakdown, and whichever markers are currently set.
+type BlockDetail struct {
+	Meta          *metadata.Meta       `json:"meta"`
+	Sizes         map[string]int64     `json:"sizes"`
+	DeletionMark  *block.DeletionMark  `json:"deletionMark,omitempty"`
+	NoCompactMark *block.NoCompactMark `json:"noCompactMark,omitempty"`
+}
+
+// AuthFunc authenticates an action request, e.g. checking a bearer token
+// or basic auth credential. It returns a human-readable reason the
+// request is rejected, or "" if the request may proceed.
+type AuthFunc func(r *http.Request) (rejectReason string)
+
+// AllowAll is an AuthFunc that never rejects a request. It exists so
+// wiring the bucket UI without an auth backend configured still works,
+// but any caller exposing mark/unmark actions beyond localhost should
+// supply a real AuthFunc instead.
+func AllowAll(*http.Request) string { return "" }
+
+// WithObjstore attaches the backing bucket detail and mark/unmark actions
+// read and write to, and the AuthFunc those actions are guarded by. The
+// read-only filtering endpoints added by ParseFilter/Query work without
+// this being called; only /blocks/{id} and the action endpoints need it.
+func (b *Bucket) WithObjstore(bkt objstore.Bucket, auth AuthFunc) *Bucket {
+	b.bkt = bkt
+	b.auth = auth
+	return b
+}
+
+// Detail fetches id's BlockDetail: its meta.json, a per-file size
+// breakdown from a single bucket listing, and any deletion/no-compact
+// markers found alongside it.
+func (b *Bucket) Detail(ctx context.Context, id ulid.ULID) (*BlockDetail, error) {
+	if b.bkt == nil {
+		return nil, fmt.Errorf("bucket UI has no objstore configured")
+	}
+
+	d := &BlockDetail{Sizes: map[string]int64{}}
+	dir := id.String() + "/"
+	err := b.bkt.IterWithAttributes(ctx, dir, func(name string, attrs objstore.ObjectAttributes) error {
+		d.Sizes[strings.TrimPrefix(name, dir)] = attrs.Size
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing block %s: %w", id, err)
+	}
+
+	rc, err := b.bkt.Get(ctx, dir+metadata.MetaFilename)
+	if err != nil {
+		return nil, fmt.Errorf("fetching meta.json for block %s: %w", id, err)
+	}
+	defer rc.Close()
+	m, err := metadata.ParseMeta(rc)
+	if err != nil {
+		return nil, err
+	}
+	d.Meta = m
+
+	if exists, err := b.bkt.Exists(ctx, dir+block.DeletionMarkFilename); err == nil && exists {
+		rc, err := b.bkt.Get(ctx, dir+block.DeletionMarkFilename)
+		if err == nil {
+			var dm block.DeletionMark
+			if json.NewDecoder(rc).Decode(&dm) == nil {
+				d.DeletionMark = &dm
+			}
+			rc.Close()
+		}
+	}
+	if exists, err := b.bkt.Exists(ctx, dir+block.NoCompactMarkFilename); err == nil && exists {
+		rc, err := b.bkt.Get(ctx, dir+block.NoCompactMarkFilename)
+		if err == nil {
+			var ncm block.NoCompactMark
+			if json.NewDecoder(rc).Decode(&ncm) == nil {
+				d.NoCompactMark = &ncm
+			}
+			rc.Close()
+		}
+	}
+
+	return d, nil
+}
+
+func (b *Bucket) serveDetail(w http.ResponseWriter, r *http.Request) {
+	id, err := ulid.Parse(strings.TrimPrefix(r.URL.Path, "/blocks/"))
+	if err != nil {
+		http.Error(w, "invalid block id", http.StatusBadRequest)
+		return
+	}
+	d, err := b.Detail(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(d)
+}
+
+func (b *Bucket) requireAuth(w http.ResponseWriter, r *http.Request) bool {
+	auth := b.auth
+	if auth == nil {
+		auth = AllowAll
+	}
+	if reason := auth(r); reason != "" {
+		http.Error(w, reason, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (b *Bucket) serveMarkDeletion(w http.ResponseWriter, r *http.Request) {
+	if !b.requireAuth(w, r) {
+		return
+	}
+	id, err := ulid.Parse(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/blocks/"), "/mark-deletion"))
+	if err != nil {
+		http.Error(w, "invalid block id", http.StatusBadRequest)
+		return
+	}
+	details := r.URL.Query().Get("details")
+	if err := block.MarkForDeletion(r.Context(), b.bkt, id, details, timeNowUnix()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (b *Bucket) serveMarkNoCompact(w http.ResponseWriter, r *http.Request) {
+	if !b.requireAuth(w, r) {
+		return
+	}
+	id, err := ulid.Parse(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/blocks/"), "/mark-no-compact"))
+	if err != nil {
+		http.Error(w, "invalid block id", http.StatusBadRequest)
+		return
+	}
+	details := r.URL.Query().Get("details")
+	if err := block.MarkForNoCompact(r.Context(), b.bkt, id, details, timeNowUnix()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// timeNowUnix is a var so tests (were this package to gain any) could
+// substitute a fixed clock; markers otherwise record the wall-clock time
+// the action was taken.
+var timeNowUnix = func() int64 { return time.Now().Unix() }
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/thanos-io/thanos/pkg/compact"
+)
+
+// Compact serves the compactor's own block views: "loaded" (the inventory
+// of the compactor's current sync, unfiltered) and "global" (the same
+// inventory restricted to what the compactor's selector actually
+// considers), each block annotated with its compact.Status so an operator
+// can tell why a block hasn't been compacted without reading logs.
+type Compact struct {
+	mu     sync.RWMutex
+	loaded []compact.BlockStatus
+	global []compact.BlockStatus
+}
+
+// NewCompact returns an empty Compact; callers refresh its state via Set
+// after each sync/planning cycle.
+func NewCompact() *Compact {
+	return &Compact{}
+}
+
+// Set replaces the current loaded and global views. Both are expected to
+// already be annotated via compact.Annotate.
+func (c *Compact) Set(loaded, global []compact.BlockStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loaded = loaded
+	c.global = global
+}
+
+// Handler serves "/loaded" and "/global" as JSON lists of BlockStatus.
+func (c *Compact) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/loaded", c.serve(func() []compact.BlockStatus { return c.loaded }))
+	mux.HandleFunc("/global", c.serve(func() []compact.BlockStatus { return c.global }))
+	return mux
+}
+
+func (c *Compact) serve(view func() []compact.BlockStatus) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		c.mu.RLock()
+		statuses := view()
+		c.mu.RUnlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(struct {
+			Blocks []compact.BlockStatus `json:"blocks"`
+		}{Blocks: statuses})
+	}
+}
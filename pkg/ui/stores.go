@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"net/http"
+)
+
+// storesIndexHTML is the querier's stores page shell: a table of
+// discovered StoreAPI endpoints, their current health and advertised
+// capabilities, each with a small sparkline rendered from its recent
+// health history. Data comes from polling /api/v1/stores rather than
+// being rendered into the page.
+const storesIndexHTML = `<!DOCTYPE html>
+<html>
+<head><title>Thanos Query - Stores</title></head>
+<body>
+<table id="stores"><thead><tr>
+  <th>Address</th><th>Strict</th><th>Healthy</th><th>Component</th><th>Min Time</th><th>Max Time</th>
+  <th>Label Sets</th><th>Last Error</th><th>History</th>
+</tr></thead><tbody></tbody></table>
+<script>
+function sparkline(history) {
+  if (!history || !history.length) return '';
+  return history.map(function(p) { return p.healthy ? '.' : 'x'; }).join('');
+}
+
+function loadStores() {
+  fetch('/api/v1/stores')
+    .then(function(r) { return r.json(); })
+    .then(function(resp) {
+      var rows = resp.data || [];
+      var body = document.querySelector('#stores tbody');
+      body.innerHTML = '';
+      rows.forEach(function(s) {
+        var row = body.insertRow();
+        row.insertCell().textContent = s.addr;
+        row.insertCell().textContent = s.strict;
+        row.insertCell().textContent = s.healthy;
+        row.insertCell().textContent = s.componentType || '';
+        row.insertCell().textContent = s.minTime;
+        row.insertCell().textContent = s.maxTime;
+        row.insertCell().textContent = JSON.stringify(s.labelSets || []);
+        row.insertCell().textContent = s.lastError || '';
+        row.insertCell().textContent = sparkline(s.history);
+      });
+    });
+}
+
+loadStores();
+setInterval(loadStores, 5000);
+</script>
+</body>
+</html>
+`
+
+// StoresHandler serves the querier's stores page shell at "/". The data
+// it polls is served separately by pkg/api/stores/v1, which renders the
+// same query.StoreSet this page's fetch() call expects.
+func StoresHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(storesIndexHTML))
+	})
+}